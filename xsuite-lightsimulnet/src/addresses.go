@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/hashing/keccak"
+	executor "github.com/multiversx/mx-chain-scenario-go/scenario/executor"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+	"github.com/multiversx/mx-chain-vm-common-go/parsers"
+	vmScenario "github.com/multiversx/mx-chain-vm-go/scenario"
+	"github.com/multiversx/mx-chain-vm-go/vmhost"
+	"github.com/multiversx/mx-chain-vm-go/vmhost/hostCore"
+	"github.com/multiversx/mx-chain-vm-go/vmhost/mock"
+)
+
+var wasmVMType = []byte{5, 0}
+
+// Same derivation as the node: the keccak hash of the creator address and its
+// little-endian nonce, with the VM type after the leading zeros and the shard
+// bytes of the creator at the end.
+func computeContractAddress(creatorAddress []byte, creatorNonce uint64, vmType []byte) []byte {
+	nonceBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nonceBytes, creatorNonce)
+	address := keccak.NewKeccak().Compute(string(append(append([]byte{}, creatorAddress...), nonceBytes...)))
+	numZeros := core.NumInitCharactersForScAddress - core.VMTypeLen
+	copy(address[:numZeros], make([]byte, numZeros))
+	copy(address[numZeros:core.NumInitCharactersForScAddress], vmType)
+	copy(address[len(address)-core.ShardIdentiferLen:], creatorAddress[len(creatorAddress)-core.ShardIdentiferLen:])
+	return address
+}
+
+// The VM asks its blockchain hook for the address of each contract it deploys,
+// from the tx or from another contract. The mocks set by the user still come
+// first, then the protocol derivation if enabled, else the mock one of the world.
+type addressHook struct {
+	*worldmock.MockWorld
+	protocolScAddresses bool
+}
+
+func (h *addressHook) NewAddress(creatorAddress []byte, creatorNonce uint64, vmType []byte) ([]byte, error) {
+	if !h.protocolScAddresses || h.Err != nil || getNewAddressMock(h.MockWorld, creatorAddress, creatorNonce) != nil {
+		return h.MockWorld.NewAddress(creatorAddress, creatorNonce, vmType)
+	}
+	address := computeContractAddress(creatorAddress, creatorNonce, vmType)
+	h.LastCreatedContractAddress = address
+	return address, nil
+}
+
+func getNewAddressMock(world *worldmock.MockWorld, creatorAddress []byte, creatorNonce uint64) []byte {
+	for _, mock := range world.NewAddressMocks {
+		if bytes.Equal(mock.CreatorAddress, creatorAddress) && mock.CreatorNonce == creatorNonce {
+			return mock.NewAddress
+		}
+	}
+	return nil
+}
+
+// Same VM as the default scenario one, built over the address hook.
+type vmBuilder struct {
+	*vmScenario.ScenarioVMHostBuilder
+	protocolScAddresses bool
+}
+
+func (b *vmBuilder) NewVM(world *worldmock.MockWorld, gasSchedule map[string]map[string]uint64) (executor.VMInterface, error) {
+	err := world.InitBuiltinFunctions(gasSchedule)
+	if err != nil {
+		return nil, err
+	}
+	esdtTransferParser, _ := parsers.NewESDTTransferParser(worldmock.WorldMarshalizer)
+	return hostCore.NewVMHost(
+		&addressHook{MockWorld: world, protocolScAddresses: b.protocolScAddresses},
+		&vmhost.VMHostParameters{
+			VMType:                              b.VMType,
+			OverrideVMExecutor:                  b.OverrideVMExecutor,
+			BlockGasLimit:                       10_000_000,
+			GasSchedule:                         gasSchedule,
+			BuiltInFuncContainer:                world.BuiltinFuncs.Container,
+			ProtectedKeyPrefix:                  []byte(core.ProtectedKeyPrefix),
+			ESDTTransferParser:                  esdtTransferParser,
+			EpochNotifier:                       &mock.EpochNotifierStub{},
+			EnableEpochsHandler:                 world.EnableEpochsHandler,
+			WasmerSIGSEGVPassthrough:            false,
+			Hasher:                              worldmock.DefaultHasher,
+			MapOpcodeAddressIsAllowed:           map[string]map[string]struct{}{},
+			TimeOutForSCExecutionInMilliseconds: b.TimeOutForSCExecutionInMilliseconds,
+		},
+	)
+}
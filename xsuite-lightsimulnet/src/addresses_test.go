@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+// Contract whose deploy endpoint deploys 40 children in a loop, each with the
+// code of childWasmHex:
+//
+//	(import "env" "createContract" (func (param i64 i32 i32 i32 i32 i32 i32 i32 i32) (result i32)))
+//	(func $deploy (local $i i32)
+//	  (block (loop
+//	    (br_if 1 (i32.ge_s (local.get $i) (i32.const 40)))
+//	    (drop (call 0 (i64.const 10000000) (i32.const 0) (i32.const 64) (i32.const 32) (i32.const 48) (i32.const 256) (i32.const 0) (i32.const 0) (i32.const 0)))
+//	    (local.set $i (i32.add (local.get $i) (i32.const 1)))
+//	    (br 0))))
+//	(data (i32.const 32) "\01\00")
+//	(data (i32.const 64) <childWasmHex>)
+const (
+	deployerWasmHex = "0061736d0100000001110260000060097e7f7f7f7f7f7f7f7f017f02160103656e760e637265617465436f6e7472616374000103030200000503010001071a03066d656d6f7279020004696e69740001066465706c6f7900020a390202000b3401017f02400340200041284e0d014280ade204410041c0004120413041800241004100410010001a200041016a21000c000b0b0b0b3e020041200b0201000041c0000b300061736d0100000001040160000003020100050301000107110204696e69740000066d656d6f727902000a040102000b"
+	childWasmHex    = "0061736d0100000001040160000003020100050301000107110204696e69740000066d656d6f727902000a040102000b"
+	numChildren     = 40
+)
+
+// Children deployed by a contract get the protocol address of its nonce at
+// each deploy, however many it deploys in the tx.
+func TestChildDeploysGetProtocolAddresses(t *testing.T) {
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots:        100,
+		Network:             DefaultNetworkParameters(),
+		ProtocolScAddresses: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployerCode, _ := hex.DecodeString(deployerWasmHex)
+	deployerAddress := uint64ToBytesAddress(1, true)
+	deployer := setTestAccount(t, e, deployerAddress, deployerCode)
+	sender := setTestAccount(t, e, uint64ToBytesAddress(1, false), nil)
+
+	data := base64.StdEncoding.EncodeToString([]byte("deploy"))
+	err = e.executeTx("01", RawTx{
+		Value:    "0",
+		Receiver: deployer,
+		Sender:   sender,
+		GasLimit: 500_000_000,
+		Data:     &data,
+		ChainID:  "S",
+		Version:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for nonce := uint64(0); nonce < numChildren; nonce++ {
+		childAddress := computeContractAddress(deployerAddress, nonce, wasmVMType)
+		child := e.scenexec.World.AcctMap.GetAccount(childAddress)
+		if child == nil || hex.EncodeToString(child.Code) != childWasmHex {
+			t.Fatalf("no child contract deployed at nonce %d", nonce)
+		}
+	}
+	if len(e.scenexec.World.NewAddressMocks) != 0 {
+		t.Fatal("address mocks were added to the world")
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+)
+
+const (
+	autoAdvanceTx    = "tx"
+	autoAdvanceBatch = "batch"
+)
+
+func (e *Executor) advanceBlock() {
+	currentBlockInfo := e.scenexec.World.CurrentBlockInfo
+	if currentBlockInfo == nil {
+		currentBlockInfo = &worldmock.BlockInfo{}
+	}
+	e.scenexec.World.PreviousBlockInfo = cloneBlockInfo(currentBlockInfo)
+	newBlockInfo := &worldmock.BlockInfo{
+		BlockTimestamp: currentBlockInfo.BlockTimestamp + e.network.RoundDuration,
+		BlockNonce:     currentBlockInfo.BlockNonce + 1,
+		BlockRound:     currentBlockInfo.BlockRound + 1,
+		BlockEpoch:     currentBlockInfo.BlockEpoch,
+		RandomSeed:     currentBlockInfo.RandomSeed,
+	}
+	if e.network.RoundsPerEpoch > 0 && newBlockInfo.BlockRound%e.network.RoundsPerEpoch == 0 {
+		newBlockInfo.BlockEpoch += 1
+	}
+	if len(e.masterRandomSeed) > 0 {
+		newBlockInfo.RandomSeed = deriveRandomSeed(e.masterRandomSeed, newBlockInfo.BlockRound)
+	}
+	e.scenexec.World.CurrentBlockInfo = newBlockInfo
+	if e.isMultiShard() {
+		e.processCrossShardScrs()
+	}
+}
+
+func (e *Executor) beforeBatch() {
+	if !e.mempool && e.autoAdvance == autoAdvanceBatch {
+		e.advanceBlock()
+	}
+}
+
+// A block set without seed gets the one of its round, like the blocks
+// generated.
+func (e *Executor) fillRandomSeed(blockInfo *worldmock.BlockInfo) {
+	if blockInfo.RandomSeed == nil && len(e.masterRandomSeed) > 0 {
+		blockInfo.RandomSeed = deriveRandomSeed(e.masterRandomSeed, blockInfo.BlockRound)
+	}
+}
+
+// Each round gets its own seed, derived from the master seed so that runs with
+// the same master seed are reproducible.
+func deriveRandomSeed(masterRandomSeed []byte, round uint64) *[48]byte {
+	roundBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(roundBytes, round)
+	randomSeed := sha512.Sum384(append(append([]byte{}, masterRandomSeed...), roundBytes...))
+	return &randomSeed
+}
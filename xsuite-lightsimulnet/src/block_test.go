@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+// Contract whose get_random_seed endpoint returns the seed of the current
+// block:
+//
+//	(import "env" "getBlockRandomSeed" (func (param i32)))
+//	(import "env" "finish" (func (param i32 i32)))
+//	(func $get_random_seed
+//	  (call 0 (i32.const 0))
+//	  (call 1 (i32.const 0) (i32.const 48)))
+const randomSeedWasmHex = "0061736d01000000010d0360000060017f0060027f7f0002270203656e7612676574426c6f636b52616e646f6d53656564000103656e760666696e697368000203030200000503010001072303066d656d6f7279020004696e697400020f6765745f72616e646f6d5f7365656400030a110202000b0c00410010004100413010010b"
+
+const testMasterRandomSeed = "0badc0de"
+
+// The seeds of rounds 1 and 2 for testMasterRandomSeed.
+var testRandomSeeds = []string{
+	"ceffcf0ef83f738eeb7ef1c955161205d75870421ba94886aa460bcf62bdf78d0309eb9a0fa3f54fe6fc22aece5b5a9e",
+	"9dea1ee7807f3098bf8a61697603dfd293910424b7706112e55e495cd6977e1a82433ad1c3987ae27a3bb216bcebc773",
+}
+
+// The seeds are sha384(masterRandomSeed || bigEndian(round)), pinned so that
+// runs started with the same --random-seed keep seeing the same seeds.
+func TestAdvanceBlockDerivesRandomSeeds(t *testing.T) {
+	config := ExecutorConfig{
+		MaxSnapshots: 100,
+		Network:      DefaultNetworkParameters(),
+		RandomSeed:   testMasterRandomSeed,
+	}
+	e, err := NewExecutor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expectedSeed := range testRandomSeeds {
+		e.advanceBlock()
+		blockInfo := e.scenexec.World.CurrentBlockInfo
+		if blockInfo.BlockRound != uint64(i+1) {
+			t.Fatalf("expected round %d, got %d", i+1, blockInfo.BlockRound)
+		}
+		if seed := hex.EncodeToString(blockInfo.RandomSeed[:]); seed != expectedSeed {
+			t.Fatalf("round %d: expected seed %s, got %s", i+1, expectedSeed, seed)
+		}
+	}
+}
+
+// A block set without seed gets the seed of its round, which the contracts read.
+func TestSetBlockInfoDerivesRandomSeed(t *testing.T) {
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots: 100,
+		Network:      DefaultNetworkParameters(),
+		RandomSeed:   testMasterRandomSeed,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := hex.DecodeString(randomSeedWasmHex)
+	contract := setTestAccount(t, e, uint64ToBytesAddress(1, true), code)
+	reqBody, _ := json.Marshal(map[string]interface{}{"round": 2})
+	_, err = e.HandleAdminSetCurrentBlockInfo(httptest.NewRequest("POST", "/admin/set-current-block-info", bytes.NewReader(reqBody)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqBody, _ = json.Marshal(map[string]interface{}{
+		"scAddress": contract,
+		"funcName":  "get_random_seed",
+	})
+	res, err := e.HandleVmQuery(httptest.NewRequest("POST", "/vm-values/query", bytes.NewReader(reqBody)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := res.(map[string]interface{})["data"].(map[string]interface{})
+	returnData, _ := data["returnData"].([]string)
+	if len(returnData) != 1 {
+		t.Fatalf("expected the seed, got %v", data)
+	}
+	seed, _ := base64.StdEncoding.DecodeString(returnData[0])
+	if hex.EncodeToString(seed) != testRandomSeeds[1] {
+		t.Fatalf("expected seed %s, got %x", testRandomSeeds[1], seed)
+	}
+}
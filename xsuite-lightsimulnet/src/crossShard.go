@@ -0,0 +1,715 @@
+package main
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/vm"
+	model "github.com/multiversx/mx-chain-scenario-go/scenario/model"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+	"github.com/multiversx/mx-chain-vm-common-go/parsers"
+	"github.com/multiversx/mx-chain-vm-go/crypto"
+	"github.com/multiversx/mx-chain-vm-go/crypto/hashing"
+	"github.com/multiversx/mx-chain-vm-go/vmhost/contexts"
+)
+
+var callArgsParser = parsers.NewCallArgsParser()
+
+var esdtTransferParser, _ = parsers.NewESDTTransferParser(worldmock.WorldMarshalizer)
+
+var asyncHasher crypto.Hasher = hashing.NewHasher()
+
+// A smart contract result sent to another shard, executed at the next block.
+type crossShardScr struct {
+	hash                 string
+	txHash               string
+	prevTxHash           string
+	sender               []byte
+	receiver             []byte
+	originalSender       []byte
+	relayer              []byte
+	value                *big.Int
+	data                 []byte
+	gasLimit             uint64
+	gasLocked            uint64
+	gasPrice             uint64
+	callType             vm.CallType
+	asyncData            []byte
+	returnCallAfterError bool
+}
+
+// In multi-shard mode a tx is executed in the shard of its sender, where only
+// the accounts of that shard are visible. What it sends to other shards is
+// queued as smart contract results, executed at the next blocks.
+func (e *Executor) executeShardedTx(txHash string, tx *model.Transaction, receiver []byte, data []byte) (*vmcommon.VMOutput, error) {
+	world := e.scenexec.World
+	e.setSelfShard(e.getShardOf(tx.From.Value))
+	err := world.UpdateWorldStateBefore(tx.From.Value, tx.GasLimit.Value, tx.GasPrice.Value)
+	if err != nil {
+		return nil, err
+	}
+	world.CreateStateBackup()
+	e.ensureAccountInShard(receiver)
+	e.ensureAccountInShard(tx.To.Value)
+	vmInput := vmcommon.VMInput{
+		CallerAddr:         tx.From.Value,
+		CallValue:          tx.EGLDValue.Value,
+		GasPrice:           tx.GasPrice.Value,
+		GasProvided:        tx.GasLimit.Value,
+		OriginalTxHash:     e.txHashBytes(txHash),
+		CurrentTxHash:      e.txHashBytes(txHash),
+		OriginalCallerAddr: tx.From.Value,
+	}
+	var vmOutput *vmcommon.VMOutput
+	if tx.Type == model.ScDeploy {
+		vmInput.Arguments = model.JSONBytesFromTreeValues(tx.Arguments)
+		vmOutput, err = e.scenexec.GetVM().RunSmartContractCreate(&vmcommon.ContractCreateInput{
+			VMInput:              vmInput,
+			ContractCode:         tx.Code.Value,
+			ContractCodeMetadata: tx.CodeMetadata.Value,
+		})
+		if err != nil {
+			vmOutput = userErrorOutput(err)
+		}
+	} else {
+		input := &vmcommon.ContractCallInput{VMInput: vmInput, RecipientAddr: receiver}
+		if !e.isMoveBalanceData(receiver, data) {
+			input.Function, input.Arguments = parseCallData(data)
+		}
+		if world.GetShardOfAddress(receiver) == world.SelfShardID {
+			vmOutput = e.runShardedCall(input)
+		} else {
+			vmOutput = e.runCrossShardCallAtSender(input, data)
+		}
+	}
+	if vmOutput.ReturnCode != vmcommon.Ok {
+		return vmOutput, world.RollbackChanges()
+	}
+	_ = world.UpdateBalanceWithDelta(tx.From.Value, new(big.Int).Neg(tx.EGLDValue.Value))
+	e.applyShardedOutput(vmOutput)
+	return vmOutput, world.CommitChanges()
+}
+
+// Runs a call in the shard of its receiver like the node does: a built-in
+// function is processed first, followed by the contract call it carries, and
+// a call without function only moves the value.
+func (e *Executor) runShardedCall(input *vmcommon.ContractCallInput) *vmcommon.VMOutput {
+	world := e.scenexec.World
+	if e.isBuiltinFunction(input.Function) {
+		builtinOutput, err := world.BuiltinFuncs.ProcessBuiltInFunction(input)
+		if err != nil {
+			return userErrorOutput(err)
+		}
+		callInput := e.getCallAfterBuiltin(input, builtinOutput)
+		if callInput == nil {
+			return builtinOutput
+		}
+		vmOutput, err := e.scenexec.GetVM().RunSmartContractCall(callInput)
+		if err != nil {
+			return userErrorOutput(err)
+		}
+		vmOutput.Logs = append(builtinOutput.Logs, vmOutput.Logs...)
+		return vmOutput
+	}
+	if worldmock.IsSmartContractAddress(input.RecipientAddr) && (input.Function != "" || input.CallType != vm.DirectCall) {
+		vmOutput, err := e.scenexec.GetVM().RunSmartContractCall(input)
+		if err != nil {
+			return userErrorOutput(err)
+		}
+		return vmOutput
+	}
+	return &vmcommon.VMOutput{
+		ReturnCode: vmcommon.Ok,
+		OutputAccounts: map[string]*vmcommon.OutputAccount{
+			string(input.RecipientAddr): {
+				Address:      input.RecipientAddr,
+				BalanceDelta: new(big.Int).Set(input.CallValue),
+			},
+		},
+	}
+}
+
+// A built-in function transferring tokens to a contract of the same shard
+// leaves the call to make as its single output transfer to the contract.
+func (e *Executor) getCallAfterBuiltin(input *vmcommon.ContractCallInput, builtinOutput *vmcommon.VMOutput) *vmcommon.ContractCallInput {
+	if builtinOutput.ReturnCode != vmcommon.Ok {
+		return nil
+	}
+	parsedTransfers, err := esdtTransferParser.ParseESDTTransfers(input.CallerAddr, input.RecipientAddr, input.Function, input.Arguments)
+	if err != nil {
+		return nil
+	}
+	world := e.scenexec.World
+	if !worldmock.IsSmartContractAddress(parsedTransfers.RcvAddr) || world.GetShardOfAddress(parsedTransfers.RcvAddr) != world.SelfShardID {
+		return nil
+	}
+	outputAccount, ok := builtinOutput.OutputAccounts[string(parsedTransfers.RcvAddr)]
+	if !ok || len(outputAccount.OutputTransfers) != 1 {
+		return nil
+	}
+	transfer := outputAccount.OutputTransfers[0]
+	function, arguments, err := callArgsParser.ParseData(string(transfer.Data))
+	if err != nil {
+		return nil
+	}
+	callInput := &vmcommon.ContractCallInput{
+		VMInput:       input.VMInput,
+		RecipientAddr: parsedTransfers.RcvAddr,
+		Function:      function,
+	}
+	callInput.Arguments = arguments
+	// The callback arguments follow the tokens, without function.
+	if input.CallType == vm.AsynchronousCallBack && parsedTransfers.CallFunction != "" {
+		callInput.Arguments = append([][]byte{[]byte(parsedTransfers.CallFunction)}, parsedTransfers.CallArgs...)
+	}
+	callInput.CallValue = big.NewInt(0)
+	callInput.GasProvided = transfer.GasLimit
+	callInput.GasLocked = transfer.GasLocked
+	callInput.ESDTTransfers = parsedTransfers.ESDTTransfers
+	return callInput
+}
+
+// Only the part of the sender runs for a receiver in another shard: a
+// built-in function takes the tokens from the sender, and the call itself is
+// carried to the receiver by a smart contract result.
+func (e *Executor) runCrossShardCallAtSender(input *vmcommon.ContractCallInput, data []byte) *vmcommon.VMOutput {
+	vmOutput := &vmcommon.VMOutput{
+		ReturnCode:   vmcommon.Ok,
+		GasRemaining: input.GasProvided,
+	}
+	if e.isBuiltinFunction(input.Function) {
+		var err error
+		vmOutput, err = e.scenexec.World.BuiltinFuncs.ProcessBuiltInFunction(input)
+		if err != nil {
+			return userErrorOutput(err)
+		}
+		outputAccount, ok := vmOutput.OutputAccounts[string(input.RecipientAddr)]
+		if vmOutput.ReturnCode != vmcommon.Ok || ok && len(outputAccount.OutputTransfers) > 0 {
+			return vmOutput
+		}
+	}
+	if vmOutput.OutputAccounts == nil {
+		vmOutput.OutputAccounts = map[string]*vmcommon.OutputAccount{}
+	}
+	vmOutput.OutputAccounts[string(input.RecipientAddr)] = &vmcommon.OutputAccount{
+		Address:      input.RecipientAddr,
+		BalanceDelta: big.NewInt(0),
+		OutputTransfers: []vmcommon.OutputTransfer{
+			{
+				Value:         input.CallValue,
+				GasLimit:      vmOutput.GasRemaining,
+				Data:          data,
+				CallType:      vm.DirectCall,
+				SenderAddress: input.CallerAddr,
+			},
+		},
+	}
+	vmOutput.GasRemaining = 0
+	return vmOutput
+}
+
+// The accounts of other shards are left to the smart contract results sent
+// to them.
+func (e *Executor) applyShardedOutput(vmOutput *vmcommon.VMOutput) {
+	world := e.scenexec.World
+	for _, outputAccount := range vmOutput.OutputAccounts {
+		if world.GetShardOfAddress(outputAccount.Address) != world.SelfShardID {
+			continue
+		}
+		account := *outputAccount
+		if account.BalanceDelta == nil {
+			account.BalanceDelta = big.NewInt(0)
+		}
+		world.UpdateAccountFromOutputAccount(&account)
+	}
+	for _, address := range vmOutput.DeletedAccounts {
+		if world.GetShardOfAddress(address) == world.SelfShardID {
+			world.AcctMap.DeleteAccount(address)
+		}
+	}
+}
+
+// The output transfers to accounts that the VM saw in another shard are
+// queued, with the same hashes as their smart contract results.
+func (e *Executor) queueOutputTransfers(
+	prevTxHash string,
+	txHash string,
+	originalSender []byte,
+	relayer []byte,
+	defaultSender []byte,
+	gasPrice uint64,
+	vmOutput *vmcommon.VMOutput,
+) (int, error) {
+	world := e.scenexec.World
+	numQueued := 0
+	for i, t := range getSortedOutputTransfers(vmOutput) {
+		if world.GetShardOfAddress(t.receiver) == world.SelfShardID {
+			continue
+		}
+		sender := t.transfer.SenderAddress
+		if len(sender) == 0 {
+			sender = defaultSender
+		}
+		value := t.transfer.Value
+		if value == nil {
+			value = big.NewInt(0)
+		}
+		hash, err := e.getScrHash(
+			i,
+			sender,
+			t.receiver,
+			originalSender,
+			value,
+			t.transfer.Data,
+			t.transfer.GasLimit,
+			gasPrice,
+			t.transfer.CallType,
+			prevTxHash,
+			txHash,
+		)
+		if err != nil {
+			return 0, err
+		}
+		e.crossShardScrs = append(e.crossShardScrs, crossShardScr{
+			hash:           hash,
+			txHash:         txHash,
+			prevTxHash:     prevTxHash,
+			sender:         sender,
+			receiver:       t.receiver,
+			originalSender: originalSender,
+			relayer:        relayer,
+			value:          new(big.Int).Set(value),
+			data:           t.transfer.Data,
+			gasLimit:       t.transfer.GasLimit,
+			gasLocked:      t.transfer.GasLocked,
+			gasPrice:       gasPrice,
+			callType:       t.transfer.CallType,
+			asyncData:      t.transfer.AsyncData,
+		})
+		numQueued += 1
+	}
+	return numQueued, nil
+}
+
+// The results sent during the previous block are executed, those they send
+// in turn waiting for the next block.
+func (e *Executor) processCrossShardScrs() {
+	removeEsdtSystemScAccount := e.placeEsdtSystemScAccount()
+	defer removeEsdtSystemScAccount()
+	scrs := e.crossShardScrs
+	e.crossShardScrs = []crossShardScr{}
+	for _, scr := range scrs {
+		err := e.executeCrossShardScr(scr)
+		if err != nil {
+			e.failCrossShardScr(scr, err)
+		}
+	}
+}
+
+// A result whose outcome cannot be recorded fails its tx, the changes it
+// already committed being kept.
+func (e *Executor) failCrossShardScr(scr crossShardScr, err error) {
+	e.txProcessStatusResps[scr.txHash] = map[string]interface{}{
+		"status": "failed",
+	}
+	events := []interface{}{}
+	event, eventErr := getLogEventData(&vmcommon.LogEntry{
+		Identifier: []byte(core.SignalErrorOperation),
+		Address:    scr.sender,
+		Topics:     [][]byte{scr.receiver, []byte(err.Error())},
+	})
+	if eventErr == nil {
+		events = append(events, event)
+	}
+	e.updateCrossShardTxResp(scr.txHash, events, nil, 0, big.NewInt(0))
+}
+
+// With a single shard, only the calls to the ESDT system smart contract leave
+// the shard. They are carried out right away, with all that they send back.
+func (e *Executor) drainCrossShardScrs() {
+	for len(e.crossShardScrs) > 0 {
+		e.processCrossShardScrs()
+	}
+	e.setSelfShard(0)
+}
+
+// The scenario executor credits all the output accounts, while those of other
+// shards only get their value once their results are executed.
+func (e *Executor) revertForeignCredits(vmOutput *vmcommon.VMOutput) {
+	world := e.scenexec.World
+	for _, outputAccount := range vmOutput.OutputAccounts {
+		if outputAccount.BalanceDelta == nil || world.GetShardOfAddress(outputAccount.Address) == world.SelfShardID {
+			continue
+		}
+		_ = world.UpdateBalanceWithDelta(outputAccount.Address, new(big.Int).Neg(outputAccount.BalanceDelta))
+	}
+}
+
+// Simplifications: the refund of a step is credited to the original sender
+// right away, its developer reward is not accounted, and accounts unknown
+// before a step are considered in shard 0 until it ends.
+func (e *Executor) executeCrossShardScr(scr crossShardScr) error {
+	world := e.scenexec.World
+	e.setSelfShard(e.getShardOf(scr.receiver))
+	world.CreateStateBackup()
+	e.ensureAccountInShard(scr.receiver)
+	input := &vmcommon.ContractCallInput{
+		VMInput: vmcommon.VMInput{
+			CallerAddr:           scr.sender,
+			CallValue:            scr.value,
+			CallType:             scr.callType,
+			GasPrice:             scr.gasPrice,
+			GasProvided:          scr.gasLimit,
+			GasLocked:            scr.gasLocked,
+			OriginalTxHash:       e.txHashBytes(scr.txHash),
+			CurrentTxHash:        e.scrHashBytes(scr.txHash, scr.hash),
+			PrevTxHash:           e.scrHashBytes(scr.txHash, scr.prevTxHash),
+			OriginalCallerAddr:   scr.originalSender,
+			ReturnCallAfterError: scr.returnCallAfterError,
+			AsyncArguments:       getAsyncArguments(scr.callType, scr.asyncData),
+		},
+		RecipientAddr: scr.receiver,
+	}
+	if !e.isMoveBalanceData(scr.receiver, scr.data) {
+		input.Function, input.Arguments = parseCallData(scr.data)
+	}
+	var vmOutput *vmcommon.VMOutput
+	if isEsdtSystemSc(scr.receiver) {
+		vmOutput = e.runEsdtSystemScCall(input)
+	} else {
+		vmOutput = e.runShardedCall(input)
+	}
+	failed := vmOutput.ReturnCode != vmcommon.Ok
+	var transferBackData []byte
+	if !failed && scr.callType == vm.AsynchronousCall && isEsdtSystemSc(scr.receiver) {
+		transferBackData = takeEsdtSystemScTransferBack(vmOutput, scr.sender)
+	}
+	if failed {
+		_ = world.RollbackChanges()
+	} else {
+		e.applyShardedOutput(vmOutput)
+		_ = world.CommitChanges()
+		err := e.updateEsdtSupplies(vmOutput.Logs)
+		if err != nil {
+			return err
+		}
+	}
+	logEntries := append([]*vmcommon.LogEntry{}, vmOutput.Logs...)
+	scrsData := []interface{}{}
+	newScrs := []crossShardScr{}
+	if !failed {
+		var err error
+		scrsData, err = e.getOutputTransfersScrsData(scr.hash, scr.txHash, scr.originalSender, scr.receiver, scr.gasPrice, vmOutput)
+		if err != nil {
+			return err
+		}
+		_, err = e.queueOutputTransfers(scr.hash, scr.txHash, scr.originalSender, scr.relayer, scr.receiver, scr.gasPrice, vmOutput)
+		if err != nil {
+			return err
+		}
+	}
+	returnScr := crossShardScr{
+		txHash:         scr.txHash,
+		prevTxHash:     scr.hash,
+		sender:         scr.receiver,
+		receiver:       scr.sender,
+		originalSender: scr.originalSender,
+		relayer:        scr.relayer,
+		value:          big.NewInt(0),
+		gasPrice:       scr.gasPrice,
+		callType:       vm.DirectCall,
+	}
+	if scr.callType == vm.AsynchronousCall {
+		callback := returnScr
+		callback.callType = vm.AsynchronousCallBack
+		callback.gasLimit = scr.gasLocked
+		callback.asyncData = getCallbackAsyncData(input.AsyncArguments)
+		if failed {
+			callback.value = scr.value
+			callback.data = []byte("@" + hex.EncodeToString(contexts.ReturnCodeToBytes(vmOutput.ReturnCode)) +
+				"@" + hex.EncodeToString([]byte(vmOutput.ReturnMessage)))
+		} else {
+			callback.gasLimit += vmOutput.GasRemaining
+			callback.data = []byte(string(transferBackData) + "@" + hex.EncodeToString(contexts.ReturnCodeToBytes(vmOutput.ReturnCode)) +
+				getReturnDataSuffix(vmOutput.ReturnData))
+		}
+		newScrs = append(newScrs, callback)
+	}
+	if failed && !scr.returnCallAfterError && scr.callType != vm.AsynchronousCallBack {
+		if scr.callType == vm.DirectCall && scr.value.Sign() > 0 {
+			valueReturn := returnScr
+			valueReturn.value = scr.value
+			valueReturn.returnCallAfterError = true
+			newScrs = append(newScrs, valueReturn)
+		}
+		if tokensData := getTokensReturnData(input.Function, input.Arguments); tokensData != "" {
+			tokensReturn := returnScr
+			tokensReturn.data = []byte(tokensData)
+			tokensReturn.returnCallAfterError = true
+			newScrs = append(newScrs, tokensReturn)
+		}
+	}
+	for _, newScr := range newScrs {
+		scrData, err := e.getScrData(
+			len(scrsData),
+			newScr.sender,
+			newScr.receiver,
+			newScr.originalSender,
+			newScr.value,
+			string(newScr.data),
+			newScr.gasLimit,
+			newScr.gasPrice,
+			newScr.callType,
+			newScr.prevTxHash,
+			newScr.txHash,
+		)
+		if err != nil {
+			return err
+		}
+		newScr.hash = scrData["hash"].(string)
+		scrsData = append(scrsData, scrData)
+		e.crossShardScrs = append(e.crossShardScrs, newScr)
+	}
+	var gasRefunded uint64
+	refund := big.NewInt(0)
+	if !failed && scr.callType != vm.AsynchronousCall && vmOutput.GasRemaining > 0 {
+		gasRefunded = vmOutput.GasRemaining
+		refund.Mul(
+			new(big.Int).SetUint64(gasRefunded),
+			new(big.Int).SetUint64(e.gasPriceForProcessing(scr.gasPrice)),
+		)
+		// The gas of a relayed tx is refunded to the relayer, which paid it.
+		refundReceiver := scr.originalSender
+		if len(scr.relayer) > 0 {
+			refundReceiver = scr.relayer
+		}
+		_ = world.UpdateBalanceWithDelta(refundReceiver, refund)
+		scrData, err := e.getScrData(
+			len(scrsData),
+			scr.receiver,
+			refundReceiver,
+			scr.originalSender,
+			refund,
+			"@"+hex.EncodeToString([]byte(vmcommon.Ok.String()))+getReturnDataSuffix(vmOutput.ReturnData),
+			0,
+			scr.gasPrice,
+			vm.DirectCall,
+			scr.hash,
+			scr.txHash,
+		)
+		if err != nil {
+			return err
+		}
+		scrsData = append(scrsData, scrData)
+		if e.accumulateFees {
+			e.totalFees.Sub(e.totalFees, refund)
+		}
+	}
+	if failed && scr.callType != vm.AsynchronousCall {
+		logEntries = append(logEntries, &vmcommon.LogEntry{
+			Identifier: []byte(core.SignalErrorOperation),
+			Address:    scr.sender,
+			Topics:     [][]byte{scr.receiver, []byte(vmOutput.ReturnMessage)},
+			Data:       [][]byte{[]byte("@" + hex.EncodeToString([]byte(vmOutput.ReturnCode.String())))},
+		})
+		e.txProcessStatusResps[scr.txHash] = map[string]interface{}{
+			"status": "failed",
+		}
+	}
+	if !e.hasCrossShardScrs(scr.txHash) && e.getTxProcessStatus(scr.txHash) != "failed" {
+		logEntries = append(logEntries, &vmcommon.LogEntry{
+			Identifier: []byte(core.CompletedTxEventIdentifier),
+			Address:    scr.receiver,
+			Topics:     [][]byte{e.txHashBytes(scr.txHash)},
+		})
+	}
+	events := []interface{}{}
+	for _, logEntry := range logEntries {
+		event, err := getLogEventData(logEntry)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+	e.updateCrossShardTxResp(scr.txHash, events, scrsData, gasRefunded, refund)
+	return nil
+}
+
+func (e *Executor) hasCrossShardScrs(txHash string) bool {
+	for _, scr := range e.crossShardScrs {
+		if scr.txHash == txHash {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Executor) getTxProcessStatus(txHash string) string {
+	processStatusResp, _ := e.txProcessStatusResps[txHash].(map[string]interface{})
+	processStatus, _ := processStatusResp["status"].(string)
+	return processStatus
+}
+
+// Stored tx responses are returned as is by the handlers and encoded after the
+// lock is released, so they are replaced rather than updated in place. The tx
+// completes once no result is left for it.
+func (e *Executor) updateCrossShardTxResp(
+	txHash string,
+	events []interface{},
+	scrsData []interface{},
+	gasRefunded uint64,
+	refund *big.Int,
+) {
+	if !e.hasCrossShardScrs(txHash) && e.getTxProcessStatus(txHash) == "pending" {
+		e.txProcessStatusResps[txHash] = map[string]interface{}{
+			"status": "success",
+		}
+	}
+	resp, ok := e.txResps[txHash].(map[string]interface{})
+	if !ok {
+		return
+	}
+	tx, ok := resp["transaction"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	newTx := make(map[string]interface{}, len(tx))
+	for k, v := range tx {
+		newTx[k] = v
+	}
+	newLogs := map[string]interface{}{}
+	if logs, ok := tx["logs"].(map[string]interface{}); ok {
+		for k, v := range logs {
+			newLogs[k] = v
+		}
+	}
+	prevEvents, _ := newLogs["events"].([]interface{})
+	newLogs["events"] = append(append([]interface{}{}, prevEvents...), events...)
+	newTx["logs"] = newLogs
+	if len(scrsData) > 0 {
+		prevScrsData, _ := tx["smartContractResults"].([]interface{})
+		newTx["smartContractResults"] = append(append([]interface{}{}, prevScrsData...), scrsData...)
+	}
+	if gasRefunded > 0 {
+		switch gasUsed := tx["gasUsed"].(type) {
+		case uint64:
+			newTx["gasUsed"] = gasUsed - gasRefunded
+		case float64:
+			newTx["gasUsed"] = gasUsed - float64(gasRefunded)
+		}
+		if feeStr, ok := tx["fee"].(string); ok {
+			if fee, err := stringToBigint(feeStr); err == nil {
+				newTx["fee"] = new(big.Int).Sub(fee, refund).String()
+			}
+		}
+	}
+	if !e.hasCrossShardScrs(txHash) && tx["status"] == "pending" {
+		newTx["status"] = "success"
+	}
+	newResp := make(map[string]interface{}, len(resp))
+	for k, v := range resp {
+		newResp[k] = v
+	}
+	newResp["transaction"] = newTx
+	e.txResps[txHash] = newResp
+}
+
+func (e *Executor) isBuiltinFunction(function string) bool {
+	if e.scenexec.World.BuiltinFuncs == nil {
+		return false
+	}
+	_, isBuiltinFunction := e.scenexec.World.BuiltinFuncs.GetBuiltinFunctionNames()[function]
+	return isBuiltinFunction
+}
+
+// The hashes of the results are hex strings, unlike the tx hashes when they
+// are counters.
+func (e *Executor) scrHashBytes(txHash string, hash string) []byte {
+	if hash == txHash {
+		return e.txHashBytes(txHash)
+	}
+	hashBytes, _ := hex.DecodeString(hash)
+	return hashBytes
+}
+
+// Unlike the calls, the callbacks and the async data have no function, their
+// data starting with the separator.
+func parseCallData(data []byte) (string, [][]byte) {
+	arguments, err := callArgsParser.ParseArguments(string(data))
+	if err != nil || len(arguments) == 0 {
+		return "", nil
+	}
+	return string(arguments[0]), arguments[1:]
+}
+
+func getAsyncArguments(callType vm.CallType, asyncData []byte) *vmcommon.AsyncArguments {
+	_, arguments := parseCallData(asyncData)
+	if callType == vm.AsynchronousCall && len(arguments) >= 2 {
+		return &vmcommon.AsyncArguments{
+			CallID:       arguments[0],
+			CallerCallID: arguments[1],
+		}
+	}
+	if callType == vm.AsynchronousCallBack && len(arguments) >= 4 {
+		return &vmcommon.AsyncArguments{
+			CallID:                       arguments[0],
+			CallerCallID:                 arguments[1],
+			CallbackAsyncInitiatorCallID: arguments[2],
+			GasAccumulated:               new(big.Int).SetBytes(arguments[3]).Uint64(),
+		}
+	}
+	return nil
+}
+
+func getCallbackAsyncData(asyncArguments *vmcommon.AsyncArguments) []byte {
+	asyncData := ""
+	for _, argument := range contexts.CreateCallbackAsyncParams(asyncHasher, asyncArguments) {
+		asyncData += "@" + hex.EncodeToString(argument)
+	}
+	return []byte(asyncData)
+}
+
+func getReturnDataSuffix(returnData [][]byte) string {
+	suffix := ""
+	for _, data := range returnData {
+		suffix += "@" + hex.EncodeToString(data)
+	}
+	return suffix
+}
+
+// The tokens of a failed transfer go back to their sender, in the format the
+// transfer had at its receiver.
+func getTokensReturnData(function string, arguments [][]byte) string {
+	numArguments := 0
+	switch function {
+	case core.BuiltInFunctionESDTTransfer:
+		numArguments = 2
+	case core.BuiltInFunctionESDTNFTTransfer:
+		numArguments = 3
+	case core.BuiltInFunctionMultiESDTNFTTransfer:
+		if len(arguments) == 0 {
+			return ""
+		}
+		// The count is checked before the multiplication, which could overflow.
+		numTransfers := new(big.Int).SetBytes(arguments[0])
+		if !numTransfers.IsUint64() || numTransfers.Uint64() > uint64((len(arguments)-1)/3) {
+			return ""
+		}
+		numArguments = 1 + 3*int(numTransfers.Uint64())
+	default:
+		return ""
+	}
+	if len(arguments) < numArguments {
+		return ""
+	}
+	return function + getReturnDataSuffix(arguments[:numArguments])
+}
+
+func userErrorOutput(err error) *vmcommon.VMOutput {
+	return &vmcommon.VMOutput{
+		ReturnCode:    vmcommon.UserError,
+		ReturnMessage: err.Error(),
+	}
+}
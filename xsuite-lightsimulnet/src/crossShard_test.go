@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestFailedCrossShardScrFailsTx(t *testing.T) {
+	e := newTestExecutor(t)
+	e.txResps["hash"] = map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"status": "pending",
+		},
+	}
+	e.txProcessStatusResps["hash"] = map[string]interface{}{
+		"status": "pending",
+	}
+	e.failCrossShardScr(crossShardScr{
+		txHash:   "hash",
+		hash:     "hash",
+		sender:   uint64ToBytesAddress(1, false),
+		receiver: uint64ToBytesAddress(1, true),
+	}, errors.New("cannot encode"))
+
+	if status := e.getTxProcessStatus("hash"); status != "failed" {
+		t.Fatalf("expected process status failed, got %s", status)
+	}
+	tx := e.txResps["hash"].(map[string]interface{})["transaction"].(map[string]interface{})
+	if tx["status"] != "success" {
+		t.Fatalf("expected status success, got %v", tx["status"])
+	}
+	events := tx["logs"].(map[string]interface{})["events"].([]interface{})
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event := events[0].(map[string]interface{})
+	if event["identifier"] != "signalError" {
+		t.Fatalf("expected signalError event, got %v", event["identifier"])
+	}
+	message, _ := base64.StdEncoding.DecodeString(event["topics"].([]string)[1])
+	if string(message) != "cannot encode" {
+		t.Fatalf("expected message cannot encode, got %s", message)
+	}
+}
+
+// Once its results are all executed, a tx keeps a status other than pending.
+func TestCrossShardTxKeepsStatus(t *testing.T) {
+	e := newTestExecutor(t)
+	e.txResps["hash"] = map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"status": "invalid",
+		},
+	}
+	e.updateCrossShardTxResp("hash", []interface{}{}, nil, 0, nil)
+
+	tx := e.txResps["hash"].(map[string]interface{})["transaction"].(map[string]interface{})
+	if tx["status"] != "invalid" {
+		t.Fatalf("expected status invalid, got %v", tx["status"])
+	}
+}
+
+func TestTokensReturnDataOfMultiTransfers(t *testing.T) {
+	token := []byte("TOKEN-abcdef")
+	tests := []struct {
+		name      string
+		arguments [][]byte
+		data      string
+	}{
+		{
+			name:      "one transfer and a function",
+			arguments: [][]byte{{1}, token, {5}, {10}, []byte("fn")},
+			data:      "MultiESDTNFTTransfer@01@" + hex.EncodeToString(token) + "@05@0a",
+		},
+		{
+			name:      "missing transfers",
+			arguments: [][]byte{{2}, token, {5}, {10}},
+			data:      "",
+		},
+		{
+			name:      "count overflowing the number of arguments",
+			arguments: [][]byte{{0x30, 0, 0, 0, 0, 0, 0, 0}, token, {5}, {10}},
+			data:      "",
+		},
+		{
+			name:      "count overflowing a uint64",
+			arguments: [][]byte{{1, 0, 0, 0, 0, 0, 0, 0, 1}, token, {5}, {10}},
+			data:      "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := getTokensReturnData("MultiESDTNFTTransfer", test.arguments)
+			if data != test.data {
+				t.Fatalf("expected data %q, got %q", test.data, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/esdt"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+	"github.com/multiversx/mx-chain-scenario-go/worldmock/esdtconvert"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+)
+
+const esdtRandomSequenceLength = 6
+
+var esdtTokenKeyPrefix = []byte(core.ProtectedKeyPrefix + core.ESDTKeyIdentifier)
+var esdtRoleKeyPrefix = []byte(core.ProtectedKeyPrefix + core.ESDTRoleIdentifier + core.ESDTKeyIdentifier)
+var esdtLastNonceKeyPrefix = []byte(core.ProtectedKeyPrefix + core.ESDTNFTLatestNonceIdentifier)
+
+// The system account holds the metadata of the NFTs, and under the key of a
+// fungible token its global settings, such as whether it is paused.
+func (e *Executor) getEsdtMetaDataStorage(nonce uint64) map[string][]byte {
+	if nonce == 0 {
+		return map[string][]byte{}
+	}
+	systemAccount := e.scenexec.World.AcctMap.GetAccount(vmcommon.SystemAccountAddress)
+	if systemAccount == nil {
+		return map[string][]byte{}
+	}
+	return systemAccount.Storage
+}
+
+func (e *Executor) getAccountEsdtsData(worldAccount *worldmock.Account) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	for key := range worldAccount.Storage {
+		if !bytes.HasPrefix([]byte(key), esdtTokenKeyPrefix) {
+			continue
+		}
+		tokenName, nonce := splitEsdtTokenKeyName([]byte(key[len(esdtTokenKeyPrefix):]))
+		esdtData, err := worldAccount.GetTokenData([]byte(tokenName), nonce, e.getEsdtMetaDataStorage(nonce))
+		if err != nil {
+			return nil, err
+		}
+		if esdtData.Value.Sign() == 0 {
+			continue
+		}
+		tokenIdentifier := getEsdtTokenIdentifier(tokenName, nonce)
+		tokenData, err := getEsdtTokenData(tokenIdentifier, esdtData)
+		if err != nil {
+			return nil, err
+		}
+		data[tokenIdentifier] = tokenData
+	}
+	return data, nil
+}
+
+func (e *Executor) getAccountEsdtData(worldAccount *worldmock.Account, tokenName string, nonce uint64) (map[string]interface{}, error) {
+	esdtData, err := worldAccount.GetTokenData([]byte(tokenName), nonce, e.getEsdtMetaDataStorage(nonce))
+	if err != nil {
+		return nil, err
+	}
+	return getEsdtTokenData(tokenName, esdtData)
+}
+
+func getEsdtTokenData(tokenIdentifier string, esdtData *esdt.ESDigitalToken) (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"tokenIdentifier": tokenIdentifier,
+		"balance":         esdtData.Value.String(),
+	}
+	if len(esdtData.Properties) > 0 {
+		data["properties"] = hex.EncodeToString(esdtData.Properties)
+	}
+	metaData := esdtData.TokenMetaData
+	if metaData == nil {
+		return data, nil
+	}
+	if len(metaData.Name) > 0 {
+		data["name"] = string(metaData.Name)
+	}
+	if metaData.Nonce > 0 {
+		data["nonce"] = metaData.Nonce
+	}
+	if len(metaData.Creator) > 0 {
+		bechCreator, err := bech32Encode(metaData.Creator)
+		if err != nil {
+			return nil, err
+		}
+		data["creator"] = bechCreator
+	}
+	data["royalties"] = big.NewInt(int64(metaData.Royalties)).String()
+	if len(metaData.Hash) > 0 {
+		data["hash"] = metaData.Hash
+	}
+	if len(metaData.URIs) > 0 {
+		data["uris"] = metaData.URIs
+	}
+	if len(metaData.Attributes) > 0 {
+		data["attributes"] = metaData.Attributes
+	}
+	return data, nil
+}
+
+func (e *Executor) getAccountRolesData(worldAccount *worldmock.Account) (map[string][]string, error) {
+	data := map[string][]string{}
+	for key := range worldAccount.Storage {
+		if !bytes.HasPrefix([]byte(key), esdtRoleKeyPrefix) {
+			continue
+		}
+		tokenName := key[len(esdtRoleKeyPrefix):]
+		roles, err := esdtconvert.GetTokenRoles([]byte(tokenName), worldAccount.Storage)
+		if err != nil {
+			return nil, err
+		}
+		if len(roles) == 0 {
+			continue
+		}
+		rolesData := []string{}
+		for _, role := range roles {
+			rolesData = append(rolesData, string(role))
+		}
+		data[tokenName] = rolesData
+	}
+	return data, nil
+}
+
+func (e *Executor) getAccountRawEsdtsData(worldAccount *worldmock.Account) ([]map[string]interface{}, error) {
+	data := []map[string]interface{}{}
+	for key := range worldAccount.Storage {
+		if !bytes.HasPrefix([]byte(key), esdtTokenKeyPrefix) {
+			continue
+		}
+		tokenName, nonce := splitEsdtTokenKeyName([]byte(key[len(esdtTokenKeyPrefix):]))
+		esdtData, err := worldAccount.GetTokenData([]byte(tokenName), nonce, e.getEsdtMetaDataStorage(nonce))
+		if err != nil {
+			return nil, err
+		}
+		if esdtData.Value.Sign() == 0 {
+			continue
+		}
+		rawEsdtData := map[string]interface{}{
+			"id":     tokenName,
+			"nonce":  nonce,
+			"amount": esdtData.Value.String(),
+		}
+		if metaData := esdtData.TokenMetaData; metaData != nil {
+			rawEsdtData["type"] = core.ESDTType(esdtData.Type).String()
+			var bechCreator string
+			if len(metaData.Creator) > 0 {
+				bechCreator, err = bech32Encode(metaData.Creator)
+				if err != nil {
+					return nil, err
+				}
+			}
+			uris := []string{}
+			for _, uri := range metaData.URIs {
+				if len(uri) > 0 {
+					uris = append(uris, string(uri))
+				}
+			}
+			rawEsdtData["name"] = string(metaData.Name)
+			rawEsdtData["creator"] = bechCreator
+			rawEsdtData["royalties"] = metaData.Royalties
+			rawEsdtData["hash"] = hex.EncodeToString(metaData.Hash)
+			rawEsdtData["attributes"] = hex.EncodeToString(metaData.Attributes)
+			rawEsdtData["uris"] = uris
+		}
+		data = append(data, rawEsdtData)
+	}
+	sort.Slice(data, func(i, j int) bool {
+		if data[i]["id"] != data[j]["id"] {
+			return data[i]["id"].(string) < data[j]["id"].(string)
+		}
+		return data[i]["nonce"].(uint64) < data[j]["nonce"].(uint64)
+	})
+	return data, nil
+}
+
+func getAccountLastNoncesData(worldAccount *worldmock.Account) map[string]uint64 {
+	data := map[string]uint64{}
+	for key, value := range worldAccount.Storage {
+		if bytes.HasPrefix([]byte(key), esdtLastNonceKeyPrefix) && len(value) > 0 {
+			data[key[len(esdtLastNonceKeyPrefix):]] = big.NewInt(0).SetBytes(value).Uint64()
+		}
+	}
+	return data
+}
+
+// The metadata of the tokens with a nonce is encoded like xsuite does, so that
+// both ways of setting tokens lead to the same storage.
+func (e *Executor) setAccountEsdts(storage map[string][]byte, rawAccount RawAccount) error {
+	if rawAccount.Esdts != nil {
+		for _, rawEsdt := range *rawAccount.Esdts {
+			err := e.setAccountEsdt(storage, rawEsdt)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if rawAccount.Roles != nil {
+		for tokenName, roles := range *rawAccount.Roles {
+			if len(roles) == 0 {
+				delete(storage, string(append(esdtRoleKeyPrefix, tokenName...)))
+				continue
+			}
+			err := esdtconvert.SetTokenRolesAsStrings([]byte(tokenName), roles, storage)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if rawAccount.LastNonce != nil {
+		for tokenName, lastNonce := range *rawAccount.LastNonce {
+			err := esdtconvert.SetLastNonce([]byte(tokenName), lastNonce, storage)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Executor) setAccountEsdt(storage map[string][]byte, rawEsdt RawEsdt) error {
+	amount, err := stringToBigint(rawEsdt.Amount)
+	if err != nil {
+		return err
+	}
+	if amount.Sign() < 0 {
+		return errors.New("esdt amount must not be negative")
+	}
+	if amount.Sign() == 0 {
+		tokenKey := append(append([]byte{}, esdtTokenKeyPrefix...), rawEsdt.Id...)
+		tokenKey = append(tokenKey, big.NewInt(0).SetUint64(rawEsdt.Nonce).Bytes()...)
+		delete(storage, string(tokenKey))
+		return nil
+	}
+	tokenData := &esdt.ESDigitalToken{
+		Value: amount,
+	}
+	if rawEsdt.Nonce > 0 {
+		var creator []byte
+		if rawEsdt.Creator != "" {
+			creator, err = bech32Decode(rawEsdt.Creator)
+			if err != nil {
+				return err
+			}
+		}
+		hash, err := hex.DecodeString(rawEsdt.Hash)
+		if err != nil {
+			return err
+		}
+		attributes, err := hex.DecodeString(rawEsdt.Attributes)
+		if err != nil {
+			return err
+		}
+		uris := [][]byte{}
+		for _, uri := range rawEsdt.Uris {
+			uris = append(uris, []byte(uri))
+		}
+		if len(uris) == 0 {
+			uris = [][]byte{{}}
+		}
+		tokenData.Type, err = e.getRawEsdtType(rawEsdt)
+		if err != nil {
+			return err
+		}
+		tokenData.Reserved = []byte{1}
+		tokenData.TokenMetaData = &esdt.MetaData{
+			Nonce:      rawEsdt.Nonce,
+			Name:       []byte(rawEsdt.Name),
+			Creator:    creator,
+			Royalties:  rawEsdt.Royalties,
+			Hash:       hash,
+			URIs:       uris,
+			Attributes: attributes,
+		}
+	}
+	return esdtconvert.SetTokenData([]byte(rawEsdt.Id), rawEsdt.Nonce, tokenData, storage)
+}
+
+// The type of a token with a nonce is the one given, else the one of its
+// collection in the registry. The collections unknown to the registry default
+// to NonFungibleESDT, as xsuite encodes them.
+func (e *Executor) getRawEsdtType(rawEsdt RawEsdt) (uint32, error) {
+	if rawEsdt.Type != "" {
+		return core.ConvertESDTTypeToUint32(rawEsdt.Type)
+	}
+	token, err := e.getEsdtToken([]byte(rawEsdt.Id))
+	if err != nil {
+		return 0, err
+	}
+	if token != nil && token.Type != core.FungibleESDT {
+		return core.ConvertESDTTypeToUint32(token.Type)
+	}
+	return uint32(core.NonFungible), nil
+}
+
+// The NFTs registered by an account are the collections it owns in the
+// registry. The collections unknown to the registry, set directly on the
+// accounts, are counted for the accounts that can create their tokens.
+func (e *Executor) getAccountRegisteredNftsData(worldAccount *worldmock.Account) ([]string, error) {
+	registeredTokens, err := e.getEsdtTokens()
+	if err != nil {
+		return nil, err
+	}
+	tokens := []string{}
+	for tokenIdentifier, token := range registeredTokens {
+		if token.Type != core.FungibleESDT && bytes.Equal(token.Owner, worldAccount.Address) {
+			tokens = append(tokens, tokenIdentifier)
+		}
+	}
+	rolesData, err := e.getAccountRolesData(worldAccount)
+	if err != nil {
+		return nil, err
+	}
+	for tokenName, roles := range rolesData {
+		if _, ok := registeredTokens[tokenName]; ok {
+			continue
+		}
+		for _, role := range roles {
+			if role == core.ESDTRoleNFTCreate {
+				tokens = append(tokens, tokenName)
+				break
+			}
+		}
+	}
+	sort.Strings(tokens)
+	return tokens, nil
+}
+
+// The token keys end with the nonce bytes, right after the random sequence of
+// the token identifier.
+func splitEsdtTokenKeyName(name []byte) (string, uint64) {
+	separatorIndex := bytes.IndexByte(name, '-')
+	if separatorIndex < 0 || len(name)-separatorIndex-1 <= esdtRandomSequenceLength {
+		return string(name), 0
+	}
+	nonceIndex := separatorIndex + 1 + esdtRandomSequenceLength
+	return string(name[:nonceIndex]), big.NewInt(0).SetBytes(name[nonceIndex:]).Uint64()
+}
+
+func getEsdtTokenIdentifier(tokenName string, nonce uint64) string {
+	if nonce == 0 {
+		return tokenName
+	}
+	return tokenName + "-" + hex.EncodeToString(big.NewInt(0).SetUint64(nonce).Bytes())
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+)
+
+// Keeps the tokens apart from the other keys of the contract storage, such as
+// the ones set by the user.
+const esdtRegistryKeyPrefix = "esdtRegistry:"
+
+// A token issued through the ESDT system smart contract. It is stored as JSON
+// in the contract storage under its prefixed identifier, a format of the
+// emulator only.
+type esdtToken struct {
+	Name          string          `json:"name"`
+	Ticker        string          `json:"ticker"`
+	Type          string          `json:"type"`
+	Owner         []byte          `json:"owner"`
+	Decimals      uint64          `json:"decimals"`
+	Properties    map[string]bool `json:"properties"`
+	Paused        bool            `json:"paused"`
+	InitialMinted *big.Int        `json:"initialMinted"`
+	Minted        *big.Int        `json:"minted"`
+	Burned        *big.Int        `json:"burned"`
+}
+
+func (t *esdtToken) getSupply() *big.Int {
+	supply := new(big.Int).Add(t.InitialMinted, t.Minted)
+	return supply.Sub(supply, t.Burned)
+}
+
+func (e *Executor) getEsdtToken(tokenIdentifier []byte) (*esdtToken, error) {
+	account := e.scenexec.World.AcctMap.GetAccount(core.ESDTSCAddress)
+	if account == nil {
+		return nil, nil
+	}
+	tokenBytes, ok := account.Storage[getEsdtRegistryKey(tokenIdentifier)]
+	if !ok || len(tokenBytes) == 0 {
+		return nil, nil
+	}
+	return unmarshalEsdtToken(tokenBytes)
+}
+
+func (e *Executor) getEsdtTokens() (map[string]*esdtToken, error) {
+	tokens := map[string]*esdtToken{}
+	account := e.scenexec.World.AcctMap.GetAccount(core.ESDTSCAddress)
+	if account == nil {
+		return tokens, nil
+	}
+	for key, tokenBytes := range account.Storage {
+		tokenIdentifier, ok := strings.CutPrefix(key, esdtRegistryKeyPrefix)
+		if !ok {
+			continue
+		}
+		token, err := unmarshalEsdtToken(tokenBytes)
+		if err != nil {
+			return nil, err
+		}
+		tokens[tokenIdentifier] = token
+	}
+	return tokens, nil
+}
+
+func getEsdtRegistryKey(tokenIdentifier []byte) string {
+	return esdtRegistryKeyPrefix + string(tokenIdentifier)
+}
+
+func unmarshalEsdtToken(tokenBytes []byte) (*esdtToken, error) {
+	token := &esdtToken{}
+	err := json.Unmarshal(tokenBytes, token)
+	if err != nil {
+		return nil, err
+	}
+	if token.InitialMinted == nil {
+		token.InitialMinted = big.NewInt(0)
+	}
+	if token.Minted == nil {
+		token.Minted = big.NewInt(0)
+	}
+	if token.Burned == nil {
+		token.Burned = big.NewInt(0)
+	}
+	return token, nil
+}
+
+func setEsdtToken(vmOutput *vmcommon.VMOutput, tokenIdentifier []byte, token *esdtToken) error {
+	tokenBytes, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	outputAccount := vmOutput.OutputAccounts[string(core.ESDTSCAddress)]
+	key := getEsdtRegistryKey(tokenIdentifier)
+	outputAccount.StorageUpdates = map[string]*vmcommon.StorageUpdate{
+		key: {
+			Offset: []byte(key),
+			Data:   tokenBytes,
+		},
+	}
+	return nil
+}
+
+// The supplies are tracked from the events of the built-in functions, like the
+// protocol does. Unlike on the protocol, where each shard keeps its own share,
+// they are updated on the metachain right away.
+func (e *Executor) updateEsdtSupplies(logEntries []*vmcommon.LogEntry) error {
+	account := e.scenexec.World.AcctMap.GetAccount(core.ESDTSCAddress)
+	if account == nil {
+		return nil
+	}
+	for _, logEntry := range logEntries {
+		if len(logEntry.Topics) < 3 {
+			continue
+		}
+		minted := true
+		switch string(logEntry.Identifier) {
+		case core.BuiltInFunctionESDTLocalMint, core.BuiltInFunctionESDTNFTCreate, core.BuiltInFunctionESDTNFTAddQuantity:
+		case core.BuiltInFunctionESDTLocalBurn, core.BuiltInFunctionESDTNFTBurn, core.BuiltInFunctionESDTWipe:
+			minted = false
+		default:
+			continue
+		}
+		tokenIdentifier := logEntry.Topics[0]
+		token, err := e.getEsdtToken(tokenIdentifier)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			continue
+		}
+		value := new(big.Int).SetBytes(logEntry.Topics[2])
+		if minted {
+			token.Minted.Add(token.Minted, value)
+		} else {
+			token.Burned.Add(token.Burned, value)
+		}
+		tokenBytes, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+		account.Storage[getEsdtRegistryKey(tokenIdentifier)] = tokenBytes
+	}
+	return nil
+}
+
+func (e *Executor) getEsdtTokenPropertiesData(tokenIdentifier string) (map[string]interface{}, error) {
+	token, err := e.getEsdtToken([]byte(tokenIdentifier))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errEsdtTokenNotFound
+	}
+	bechOwner, err := bech32Encode(token.Owner)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"tokenIdentifier": tokenIdentifier,
+		"tokenName":       token.Name,
+		"tokenType":       token.Type,
+		"ownerAddress":    bechOwner,
+		"numDecimals":     token.Decimals,
+		"isPaused":        token.Paused,
+	}
+	for property, value := range token.Properties {
+		data[property] = value
+	}
+	return data, nil
+}
+
+func (e *Executor) getEsdtSupplyData(tokenIdentifier string) (map[string]interface{}, error) {
+	token, err := e.getEsdtToken([]byte(tokenIdentifier))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errEsdtTokenNotFound
+	}
+	data := map[string]interface{}{
+		"supply":        token.getSupply().String(),
+		"minted":        token.Minted.String(),
+		"burned":        token.Burned.String(),
+		"initialMinted": token.InitialMinted.String(),
+	}
+	return data, nil
+}
+
+func (e *Executor) getEsdtTokenIdentifiers() ([]string, error) {
+	tokens, err := e.getEsdtTokens()
+	if err != nil {
+		return nil, err
+	}
+	tokenIdentifiers := []string{}
+	for tokenIdentifier := range tokens {
+		tokenIdentifiers = append(tokenIdentifiers, tokenIdentifier)
+	}
+	sort.Strings(tokenIdentifiers)
+	return tokenIdentifiers, nil
+}
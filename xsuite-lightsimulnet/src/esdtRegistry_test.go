@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+)
+
+func TestEsdtTokensSkipForeignKeys(t *testing.T) {
+	e := newTestExecutor(t)
+	tokenBytes, err := json.Marshal(esdtToken{Name: "Token", Ticker: "TKN", Type: core.FungibleESDT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kvs := map[string]string{
+		hex.EncodeToString([]byte(getEsdtRegistryKey([]byte("TKN-123456")))): hex.EncodeToString(tokenBytes),
+		hex.EncodeToString([]byte("foreign")):                                "0102",
+		hex.EncodeToString([]byte("OTHER-123456")):                           hex.EncodeToString([]byte("{}")),
+		hex.EncodeToString([]byte("null")):                                   hex.EncodeToString([]byte("null")),
+	}
+	esdtScAddress, err := bech32Encode(core.ESDTSCAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = e.setAccount(RawAccount{Address: esdtScAddress, Kvs: &kvs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := e.HandleNetworkEsdts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := res.(map[string]interface{})["tokens"]
+	if !reflect.DeepEqual(tokens, []string{"TKN-123456"}) {
+		t.Fatalf("expected the tokens [TKN-123456], got %v", tokens)
+	}
+}
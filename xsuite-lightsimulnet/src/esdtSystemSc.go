@@ -0,0 +1,430 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+)
+
+const esdtMaxDecimals = 18
+
+var errEsdtInvalidArguments = errors.New("invalid arguments")
+var errEsdtTokenNotFound = errors.New("no ticker with given name")
+var errEsdtNotOwner = errors.New("can be called by owner only")
+
+// The properties of a token, with their value when not given at issuance.
+var esdtDefaultProperties = map[string]bool{
+	"canFreeze":                false,
+	"canWipe":                  false,
+	"canPause":                 false,
+	"canMint":                  false,
+	"canBurn":                  false,
+	"canChangeOwner":           false,
+	"canUpgrade":               true,
+	"canAddSpecialRoles":       true,
+	"canTransferNFTCreateRole": false,
+	"canCreateMultiShard":      false,
+}
+
+var esdtFungibleRoles = []string{
+	core.ESDTRoleLocalMint,
+	core.ESDTRoleLocalBurn,
+	core.ESDTRoleTransfer,
+}
+
+var esdtNonFungibleRoles = []string{
+	core.ESDTRoleNFTCreate,
+	core.ESDTRoleNFTBurn,
+	core.ESDTRoleNFTUpdateAttributes,
+	core.ESDTRoleNFTAddURI,
+	core.ESDTRoleTransfer,
+}
+
+var esdtSemiFungibleRoles = append([]string{core.ESDTRoleNFTAddQuantity}, esdtNonFungibleRoles...)
+
+func isEsdtSystemSc(address []byte) bool {
+	return bytes.Equal(address, core.ESDTSCAddress)
+}
+
+// The VM only sends the calls to the ESDT system smart contract to another
+// shard if it sees its account on the metachain. The account is dropped again
+// while it is empty.
+func (e *Executor) placeEsdtSystemScAccount() func() {
+	world := e.scenexec.World
+	account := world.AcctMap.GetAccount(core.ESDTSCAddress)
+	if account == nil {
+		account = world.AcctMap.CreateAccount(core.ESDTSCAddress, world)
+	}
+	account.ShardID = core.MetachainShardId
+	return func() {
+		account := world.AcctMap.GetAccount(core.ESDTSCAddress)
+		if account != nil && account.Balance.Sign() == 0 && len(account.Storage) == 0 {
+			world.AcctMap.DeleteAccount(core.ESDTSCAddress)
+		}
+	}
+}
+
+// Runs a call to the ESDT system smart contract, which has no code in the
+// world. Its gas is not metered, and it acts on the other accounts through
+// built-in functions sent to them, as the protocol does.
+func (e *Executor) runEsdtSystemScCall(input *vmcommon.ContractCallInput) *vmcommon.VMOutput {
+	vmOutput := &vmcommon.VMOutput{
+		ReturnCode:   vmcommon.Ok,
+		GasRemaining: input.GasProvided,
+		OutputAccounts: map[string]*vmcommon.OutputAccount{
+			string(core.ESDTSCAddress): {
+				Address:      core.ESDTSCAddress,
+				BalanceDelta: new(big.Int).Set(input.CallValue),
+			},
+		},
+	}
+	var err error
+	switch input.Function {
+	case "issue":
+		err = e.esdtIssue(input, vmOutput)
+	case "issueNonFungible":
+		err = e.esdtIssueWithoutDecimals(input, vmOutput, core.NonFungibleESDT)
+	case "issueSemiFungible":
+		err = e.esdtIssueWithoutDecimals(input, vmOutput, core.SemiFungibleESDT)
+	case "registerMetaESDT":
+		err = e.esdtRegisterMeta(input, vmOutput)
+	case "setSpecialRole":
+		err = e.esdtSetSpecialRole(input, vmOutput, core.BuiltInFunctionSetESDTRole)
+	case "unSetSpecialRole":
+		err = e.esdtSetSpecialRole(input, vmOutput, core.BuiltInFunctionUnSetESDTRole)
+	case "pause":
+		err = e.esdtTogglePause(input, vmOutput, true)
+	case "unPause":
+		err = e.esdtTogglePause(input, vmOutput, false)
+	case "freeze":
+		err = e.esdtFreezeOrWipe(input, vmOutput, "canFreeze", core.BuiltInFunctionESDTFreeze)
+	case "unFreeze":
+		err = e.esdtFreezeOrWipe(input, vmOutput, "canFreeze", core.BuiltInFunctionESDTUnFreeze)
+	case "wipe":
+		err = e.esdtFreezeOrWipe(input, vmOutput, "canWipe", core.BuiltInFunctionESDTWipe)
+	case "transferOwnership":
+		err = e.esdtTransferOwnership(input, vmOutput)
+	default:
+		err = errors.New("invalid method to call")
+	}
+	if err != nil {
+		return userErrorOutput(err)
+	}
+	return vmOutput
+}
+
+// issue@name@ticker@supply@decimals[@property@value...]
+func (e *Executor) esdtIssue(input *vmcommon.ContractCallInput, vmOutput *vmcommon.VMOutput) error {
+	arguments := input.Arguments
+	if len(arguments) < 4 || len(arguments)%2 != 0 {
+		return errEsdtInvalidArguments
+	}
+	supply := new(big.Int).SetBytes(arguments[2])
+	decimals := new(big.Int).SetBytes(arguments[3])
+	tokenIdentifier, err := e.issueEsdtToken(input, vmOutput, core.FungibleESDT, arguments[0], arguments[1], supply, decimals, arguments[4:])
+	if err != nil {
+		return err
+	}
+	if supply.Sign() > 0 {
+		addEsdtSystemScTransfer(vmOutput, input.CallerAddr, core.BuiltInFunctionESDTTransfer+
+			"@"+hex.EncodeToString(tokenIdentifier)+"@"+hex.EncodeToString(supply.Bytes()))
+	} else {
+		vmOutput.ReturnData = [][]byte{tokenIdentifier}
+	}
+	return nil
+}
+
+// issueNonFungible@name@ticker[@property@value...]
+func (e *Executor) esdtIssueWithoutDecimals(input *vmcommon.ContractCallInput, vmOutput *vmcommon.VMOutput, tokenType string) error {
+	arguments := input.Arguments
+	if len(arguments) < 2 || len(arguments)%2 != 0 {
+		return errEsdtInvalidArguments
+	}
+	tokenIdentifier, err := e.issueEsdtToken(input, vmOutput, tokenType, arguments[0], arguments[1], big.NewInt(0), big.NewInt(0), arguments[2:])
+	if err != nil {
+		return err
+	}
+	vmOutput.ReturnData = [][]byte{tokenIdentifier}
+	return nil
+}
+
+// registerMetaESDT@name@ticker@decimals[@property@value...]
+func (e *Executor) esdtRegisterMeta(input *vmcommon.ContractCallInput, vmOutput *vmcommon.VMOutput) error {
+	arguments := input.Arguments
+	if len(arguments) < 3 || len(arguments)%2 != 1 {
+		return errEsdtInvalidArguments
+	}
+	decimals := new(big.Int).SetBytes(arguments[2])
+	tokenIdentifier, err := e.issueEsdtToken(input, vmOutput, core.MetaESDT, arguments[0], arguments[1], big.NewInt(0), decimals, arguments[3:])
+	if err != nil {
+		return err
+	}
+	vmOutput.ReturnData = [][]byte{tokenIdentifier}
+	return nil
+}
+
+func (e *Executor) issueEsdtToken(
+	input *vmcommon.ContractCallInput,
+	vmOutput *vmcommon.VMOutput,
+	tokenType string,
+	name []byte,
+	ticker []byte,
+	supply *big.Int,
+	decimals *big.Int,
+	propertyArguments [][]byte,
+) ([]byte, error) {
+	if input.CallValue.Cmp(e.network.esdtIssueCost()) != 0 {
+		return nil, errors.New("callValue not equals with baseIssuingCost")
+	}
+	if !isEsdtTokenNameValid(name) {
+		return nil, errors.New("token name is not human readable")
+	}
+	if !isEsdtTickerValid(ticker) {
+		return nil, errors.New("ticker name is not valid")
+	}
+	if decimals.Cmp(big.NewInt(esdtMaxDecimals)) > 0 {
+		return nil, errors.New("invalid number of decimals")
+	}
+	properties := map[string]bool{}
+	for property, value := range esdtDefaultProperties {
+		properties[property] = value
+	}
+	for i := 0; i+1 < len(propertyArguments); i += 2 {
+		property := string(propertyArguments[i])
+		if _, ok := esdtDefaultProperties[property]; !ok {
+			return nil, errors.New("invalid argument: " + property)
+		}
+		switch string(propertyArguments[i+1]) {
+		case "true":
+			properties[property] = true
+		case "false":
+			properties[property] = false
+		default:
+			return nil, errors.New("invalid argument: " + string(propertyArguments[i+1]))
+		}
+	}
+	tokenIdentifier, err := e.newEsdtTokenIdentifier(input, ticker)
+	if err != nil {
+		return nil, err
+	}
+	token := &esdtToken{
+		Name:          string(name),
+		Ticker:        string(ticker),
+		Type:          tokenType,
+		Owner:         input.CallerAddr,
+		Decimals:      decimals.Uint64(),
+		Properties:    properties,
+		InitialMinted: supply,
+		Minted:        big.NewInt(0),
+		Burned:        big.NewInt(0),
+	}
+	err = setEsdtToken(vmOutput, tokenIdentifier, token)
+	if err != nil {
+		return nil, err
+	}
+	return tokenIdentifier, nil
+}
+
+// The random sequence of the identifier is derived from the caller and the
+// random seed like on the protocol. The tx hash is mixed in too, as the seed
+// of the light simulnet often stays the same across blocks.
+func (e *Executor) newEsdtTokenIdentifier(input *vmcommon.ContractCallInput, ticker []byte) ([]byte, error) {
+	randomBase := append(append([]byte{}, input.CallerAddr...), e.scenexec.World.CurrentRandomSeed()...)
+	randomBase = append(randomBase, input.CurrentTxHash...)
+	random, err := asyncHasher.Sha256(randomBase)
+	if err != nil {
+		return nil, err
+	}
+	tokenIdentifier := []byte(string(ticker) + "-" + hex.EncodeToString(random)[:esdtRandomSequenceLength])
+	token, err := e.getEsdtToken(tokenIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if token != nil {
+		return nil, errors.New("token identifier already exists")
+	}
+	return tokenIdentifier, nil
+}
+
+// setSpecialRole@token@address@role[@role...]
+func (e *Executor) esdtSetSpecialRole(input *vmcommon.ContractCallInput, vmOutput *vmcommon.VMOutput, builtinFunction string) error {
+	arguments := input.Arguments
+	if len(arguments) < 3 || len(arguments[1]) != len(input.CallerAddr) {
+		return errEsdtInvalidArguments
+	}
+	token, err := e.getOwnedEsdtToken(input)
+	if err != nil {
+		return err
+	}
+	if !token.Properties["canAddSpecialRoles"] {
+		return errors.New("cannot add special roles")
+	}
+	allowedRoles := esdtNonFungibleRoles
+	switch token.Type {
+	case core.FungibleESDT:
+		allowedRoles = esdtFungibleRoles
+	case core.SemiFungibleESDT, core.MetaESDT:
+		allowedRoles = esdtSemiFungibleRoles
+	}
+	data := builtinFunction + "@" + hex.EncodeToString(arguments[0])
+	for _, role := range arguments[2:] {
+		if !containsString(allowedRoles, string(role)) {
+			return errors.New("invalid argument: " + string(role))
+		}
+		data += "@" + hex.EncodeToString(role)
+	}
+	addEsdtSystemScTransfer(vmOutput, arguments[1], data)
+	return nil
+}
+
+// pause@token
+func (e *Executor) esdtTogglePause(input *vmcommon.ContractCallInput, vmOutput *vmcommon.VMOutput, paused bool) error {
+	if len(input.Arguments) != 1 {
+		return errEsdtInvalidArguments
+	}
+	token, err := e.getOwnedEsdtToken(input)
+	if err != nil {
+		return err
+	}
+	if !token.Properties["canPause"] {
+		return errors.New("cannot pause/un-pause")
+	}
+	if token.Paused == paused {
+		if paused {
+			return errors.New("cannot pause an already paused contract")
+		}
+		return errors.New("cannot unPause an already un-paused contract")
+	}
+	token.Paused = paused
+	builtinFunction := core.BuiltInFunctionESDTPause
+	if !paused {
+		builtinFunction = core.BuiltInFunctionESDTUnPause
+	}
+	addEsdtSystemScTransfer(vmOutput, vmcommon.SystemAccountAddress, builtinFunction+"@"+hex.EncodeToString(input.Arguments[0]))
+	return setEsdtToken(vmOutput, input.Arguments[0], token)
+}
+
+// freeze@token@address
+func (e *Executor) esdtFreezeOrWipe(input *vmcommon.ContractCallInput, vmOutput *vmcommon.VMOutput, property string, builtinFunction string) error {
+	arguments := input.Arguments
+	if len(arguments) != 2 || len(arguments[1]) != len(input.CallerAddr) {
+		return errEsdtInvalidArguments
+	}
+	token, err := e.getOwnedEsdtToken(input)
+	if err != nil {
+		return err
+	}
+	if !token.Properties[property] {
+		return errors.New("cannot " + input.Function)
+	}
+	addEsdtSystemScTransfer(vmOutput, arguments[1], builtinFunction+"@"+hex.EncodeToString(arguments[0]))
+	return nil
+}
+
+// transferOwnership@token@address
+func (e *Executor) esdtTransferOwnership(input *vmcommon.ContractCallInput, vmOutput *vmcommon.VMOutput) error {
+	arguments := input.Arguments
+	if len(arguments) != 2 || len(arguments[1]) != len(input.CallerAddr) {
+		return errEsdtInvalidArguments
+	}
+	token, err := e.getOwnedEsdtToken(input)
+	if err != nil {
+		return err
+	}
+	if !token.Properties["canChangeOwner"] {
+		return errors.New("cannot change owner of the token")
+	}
+	token.Owner = arguments[1]
+	return setEsdtToken(vmOutput, arguments[0], token)
+}
+
+// The token is the first argument of the calls made by its owner.
+func (e *Executor) getOwnedEsdtToken(input *vmcommon.ContractCallInput) (*esdtToken, error) {
+	if input.CallValue.Sign() != 0 {
+		return nil, errors.New("callValue must be 0")
+	}
+	token, err := e.getEsdtToken(input.Arguments[0])
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errEsdtTokenNotFound
+	}
+	if !bytes.Equal(token.Owner, input.CallerAddr) {
+		return nil, errEsdtNotOwner
+	}
+	return token, nil
+}
+
+func addEsdtSystemScTransfer(vmOutput *vmcommon.VMOutput, receiver []byte, data string) {
+	outputAccount, ok := vmOutput.OutputAccounts[string(receiver)]
+	if !ok {
+		outputAccount = &vmcommon.OutputAccount{
+			Address:      receiver,
+			BalanceDelta: big.NewInt(0),
+		}
+		vmOutput.OutputAccounts[string(receiver)] = outputAccount
+	}
+	outputAccount.OutputTransfers = append(outputAccount.OutputTransfers, vmcommon.OutputTransfer{
+		Value:         big.NewInt(0),
+		Data:          []byte(data),
+		SenderAddress: core.ESDTSCAddress,
+	})
+}
+
+// Like on the protocol, a system smart contract answers an async call with its
+// last transfer back to the caller when that transfer calls nothing, the
+// callback arguments being appended to it. Any other transfer stays apart.
+func takeEsdtSystemScTransferBack(vmOutput *vmcommon.VMOutput, caller []byte) []byte {
+	outputAccount, ok := vmOutput.OutputAccounts[string(caller)]
+	if !ok || len(outputAccount.OutputTransfers) == 0 {
+		return nil
+	}
+	lastIndex := len(outputAccount.OutputTransfers) - 1
+	data := outputAccount.OutputTransfers[lastIndex].Data
+	function, arguments := parseCallData(data)
+	parsedTransfers, err := esdtTransferParser.ParseESDTTransfers(core.ESDTSCAddress, caller, function, arguments)
+	if err != nil || parsedTransfers.CallFunction != "" {
+		return nil
+	}
+	outputAccount.OutputTransfers = outputAccount.OutputTransfers[:lastIndex]
+	return data
+}
+
+// Names are alphanumeric, and tickers are upper-case alphanumeric.
+func isEsdtTokenNameValid(name []byte) bool {
+	if len(name) < 3 || len(name) > 20 {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isEsdtTickerValid(ticker []byte) bool {
+	if len(ticker) < 3 || len(ticker) > 10 {
+		return false
+	}
+	for _, c := range ticker {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+)
+
+func getTestRawEsdts(t *testing.T, e *Executor, address []byte) []map[string]interface{} {
+	worldAccount := e.scenexec.World.AcctMap.GetAccount(address)
+	if worldAccount == nil {
+		t.Fatalf("account %x not found", address)
+	}
+	rawEsdts, err := e.getAccountRawEsdtsData(worldAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rawEsdts
+}
+
+// The SFT gets its type from the raw esdt and the MetaESDT from its collection
+// in the registry. Both keep it when they are read back and set again.
+func TestRawEsdtsRoundTrip(t *testing.T) {
+	e := newTestExecutor(t)
+	tokenBytes, err := json.Marshal(esdtToken{Name: "Meta", Ticker: "META", Type: core.MetaESDT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kvs := map[string]string{
+		hex.EncodeToString([]byte(getEsdtRegistryKey([]byte("META-123456")))): hex.EncodeToString(tokenBytes),
+	}
+	esdtScAddress, err := bech32Encode(core.ESDTSCAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = e.setAccount(RawAccount{Address: esdtScAddress, Kvs: &kvs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := uint64ToBytesAddress(1, false)
+	bechAddress := setTestAccount(t, e, address, nil)
+	err = e.setAccount(RawAccount{Address: bechAddress, Esdts: &[]RawEsdt{
+		{Id: "SFT-123456", Nonce: 1, Type: core.SemiFungibleESDT, Amount: "10", Name: "Sft"},
+		{Id: "META-123456", Nonce: 2, Amount: "1000", Name: "Meta"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawEsdts := getTestRawEsdts(t, e, address)
+	types := map[string]interface{}{}
+	for _, rawEsdt := range rawEsdts {
+		types[rawEsdt["id"].(string)] = rawEsdt["type"]
+	}
+	expectedTypes := map[string]interface{}{
+		"SFT-123456":  core.SemiFungibleESDT,
+		"META-123456": core.MetaESDT,
+	}
+	if !reflect.DeepEqual(types, expectedTypes) {
+		t.Fatalf("expected the types %v, got %v", expectedTypes, types)
+	}
+
+	rawEsdtsBytes, err := json.Marshal(rawEsdts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var readEsdts []RawEsdt
+	err = json.Unmarshal(rawEsdtsBytes, &readEsdts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAddress := uint64ToBytesAddress(2, false)
+	otherBechAddress := setTestAccount(t, e, otherAddress, nil)
+	err = e.setAccount(RawAccount{Address: otherBechAddress, Esdts: &readEsdts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRawEsdts := getTestRawEsdts(t, e, otherAddress)
+	if !reflect.DeepEqual(otherRawEsdts, rawEsdts) {
+		t.Fatalf("expected the esdts %v, got %v", rawEsdts, otherRawEsdts)
+	}
+}
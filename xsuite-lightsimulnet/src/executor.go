@@ -1,24 +1,92 @@
 package main
 
 import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+
 	executor "github.com/multiversx/mx-chain-scenario-go/scenario/executor"
 	model "github.com/multiversx/mx-chain-scenario-go/scenario/model"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
 	vmScenario "github.com/multiversx/mx-chain-vm-go/scenario"
 )
 
+// Handlers run on parallel goroutines: read-only ones hold mu for reading, the
+// others exclusively. VM queries only read the world, which they clone.
 type Executor struct {
+	mu										sync.RWMutex
 	scenexec    					*executor.ScenarioExecutor
+	queryScenexecsMu			sync.Mutex
+	queryScenexecs				[]*executor.ScenarioExecutor
 	numberOfTxsToKeep			int
 	hashesOfTxsToKeep		  []string
 	txResps								map[string]interface{}
 	txProcessStatusResps  map[string]interface{}
 	txCounter							uint64
 	scCounter							uint64
+	snapshots							map[uint64]*worldSnapshot
+	snapshotCounter				uint64
+	maxSnapshots					int
+	stateFile							string
+	stateDir							string
+	counterTxHashes				bool
+	verifySignatures			bool
+	mempool								bool
+	pendingTxs						[]pendingTx
+	autoAdvance						string
+	network								NetworkParameters
+	masterRandomSeed			[]byte
+	accumulateFees				bool
+	totalFees							*big.Int
+	totalDevRewards				*big.Int
+	protocolScAddresses		bool
+	crossShardScrs				[]crossShardScr
 }
 
-func NewExecutor() (*Executor, error) {
-	scenexec := vmScenario.DefaultScenarioExecutor()
-	err := scenexec.InitVM(model.GasScheduleDefault)
+type ExecutorConfig struct {
+	MaxSnapshots	int
+	StateFile			string
+	StateDir			string
+	CounterTxHashes	bool
+	VerifySignatures	bool
+	Mempool					bool
+	AutoAdvance			string
+	Network					NetworkParameters
+	RandomSeed			string
+	AccumulateFees	bool
+	ProtocolScAddresses	bool
+}
+
+func NewExecutor(config ExecutorConfig) (*Executor, error) {
+	if config.AutoAdvance != "" && config.AutoAdvance != autoAdvanceTx && config.AutoAdvance != autoAdvanceBatch {
+		return nil, errors.New("invalid auto-advance policy")
+	}
+	err := config.Network.validate()
+	if err != nil {
+		return nil, err
+	}
+	addressHrp = config.Network.AddressHrp
+	masterRandomSeed, err := hex.DecodeString(config.RandomSeed)
+	if err != nil {
+		return nil, err
+	}
+	stateDir := ""
+	if config.StateDir != "" {
+		stateDir, err = filepath.Abs(config.StateDir)
+		if err != nil {
+			return nil, err
+		}
+		stateDir, err = filepath.EvalSymlinks(stateDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Contracts are deployed in the shard of their creator like on the
+	// protocol, which needs their protocol address.
+	protocolScAddresses := config.ProtocolScAddresses || config.Network.NumShards > 1
+	scenexec, err := newScenexec(protocolScAddresses)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +98,40 @@ func NewExecutor() (*Executor, error) {
 		txProcessStatusResps: map[string]interface{}{},
 		txCounter: 0,
 		scCounter: 0,
+		snapshots: map[uint64]*worldSnapshot{},
+		snapshotCounter: 0,
+		maxSnapshots: config.MaxSnapshots,
+		stateFile: config.StateFile,
+		stateDir: stateDir,
+		counterTxHashes: config.CounterTxHashes,
+		verifySignatures: config.VerifySignatures,
+		mempool: config.Mempool,
+		pendingTxs: []pendingTx{},
+		autoAdvance: config.AutoAdvance,
+		network: config.Network,
+		masterRandomSeed: masterRandomSeed,
+		accumulateFees: config.AccumulateFees,
+		totalFees: big.NewInt(0),
+		totalDevRewards: big.NewInt(0),
+		protocolScAddresses: protocolScAddresses,
+		crossShardScrs: []crossShardScr{},
+	}
+	if len(masterRandomSeed) > 0 {
+		e.scenexec.World.CurrentBlockInfo = &worldmock.BlockInfo{
+			RandomSeed: deriveRandomSeed(masterRandomSeed, 0),
+		}
 	}
 	return &e, nil
 }
+
+func newScenexec(protocolScAddresses bool) (*executor.ScenarioExecutor, error) {
+	scenexec := executor.NewScenarioExecutor(&vmBuilder{
+		ScenarioVMHostBuilder: vmScenario.NewScenarioVMHostBuilder(),
+		protocolScAddresses:   protocolScAddresses,
+	})
+	err := scenexec.InitVM(model.GasScheduleDefault)
+	if err != nil {
+		return nil, err
+	}
+	return scenexec, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"math/big"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/vm"
+	model "github.com/multiversx/mx-chain-scenario-go/scenario/model"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+)
+
+const developerRewardsPercentage = 30
+
+type txFees struct {
+	gasUsed          uint64
+	initiallyPaidFee *big.Int
+	fee              *big.Int
+	refund           *big.Int
+	developerReward  *big.Int
+}
+
+// The fee is computed like on the node: the move balance gas, which covers the
+// data of the tx, is paid at the gas price and the gas left to the execution
+// is paid at the gas price times the gas price modifier. Unused execution gas
+// is refunded and a share of the used execution gas goes to the contract
+// developer.
+func (e *Executor) computeTxFees(rawTx RawTx, moveBalanceGas uint64, gasRemaining uint64) txFees {
+	executionGasLimit := rawTx.GasLimit - moveBalanceGas
+	executionGasUsed := executionGasLimit - gasRemaining
+	gasPriceForProcessing := new(big.Int).SetUint64(e.gasPriceForProcessing(rawTx.GasPrice))
+	moveBalanceFee := new(big.Int).Mul(
+		new(big.Int).SetUint64(moveBalanceGas),
+		new(big.Int).SetUint64(rawTx.GasPrice),
+	)
+	executionFee := new(big.Int).Mul(new(big.Int).SetUint64(executionGasUsed), gasPriceForProcessing)
+	refund := new(big.Int).Mul(new(big.Int).SetUint64(gasRemaining), gasPriceForProcessing)
+	fee := new(big.Int).Add(moveBalanceFee, executionFee)
+	developerReward := new(big.Int).Mul(executionFee, big.NewInt(developerRewardsPercentage))
+	developerReward.Div(developerReward, big.NewInt(100))
+	return txFees{
+		gasUsed:          rawTx.GasLimit - gasRemaining,
+		initiallyPaidFee: new(big.Int).Add(fee, refund),
+		fee:              fee,
+		refund:           refund,
+		developerReward:  developerReward,
+	}
+}
+
+func (e *Executor) gasPriceForProcessing(gasPrice uint64) uint64 {
+	return uint64(float64(gasPrice) * e.network.GasPriceModifier)
+}
+
+// The scenario executor takes the execution gas limit at the full gas price
+// from the sender, so the difference with the actual fee is settled here.
+func (e *Executor) settleTxFees(sender []byte, executionGasLimit uint64, gasPrice uint64, fees txFees) {
+	senderAccount := e.scenexec.World.AcctMap.GetAccount(sender)
+	paid := new(big.Int).Mul(
+		new(big.Int).SetUint64(executionGasLimit),
+		new(big.Int).SetUint64(gasPrice),
+	)
+	senderAccount.Balance = new(big.Int).Add(senderAccount.Balance, new(big.Int).Sub(paid, fees.fee))
+	if e.accumulateFees {
+		e.totalFees.Add(e.totalFees, fees.fee)
+	}
+}
+
+func (e *Executor) creditDeveloperReward(contract []byte, fees txFees, vmOutput *vmcommon.VMOutput) {
+	contractAccount := e.scenexec.World.AcctMap.GetAccount(contract)
+	if vmOutput.ReturnCode != vmcommon.Ok || contractAccount == nil || !contractAccount.IsSmartContract {
+		return
+	}
+	if fees.developerReward.Sign() == 0 {
+		return
+	}
+	contractAccount.AddToDeveloperReward(fees.developerReward)
+	if e.accumulateFees {
+		e.totalDevRewards.Add(e.totalDevRewards, fees.developerReward)
+	}
+}
+
+// The VM would look for a ClaimDeveloperRewards endpoint in the contract, so
+// the built-in function is called directly, after taking the nonce and the gas
+// from the sender like the scenario executor does.
+func (e *Executor) executeClaimDeveloperRewards(tx *model.Transaction) (*vmcommon.VMOutput, error) {
+	err := e.scenexec.World.UpdateWorldStateBefore(tx.From.Value, tx.GasLimit.Value, tx.GasPrice.Value)
+	if err != nil {
+		return nil, err
+	}
+	vmOutput, err := e.scenexec.World.BuiltinFuncs.ProcessBuiltInFunction(&vmcommon.ContractCallInput{
+		VMInput: vmcommon.VMInput{
+			CallerAddr:  tx.From.Value,
+			CallValue:   tx.EGLDValue.Value,
+			GasPrice:    tx.GasPrice.Value,
+			GasProvided: tx.GasLimit.Value,
+			CallType:    vm.DirectCall,
+		},
+		RecipientAddr: tx.To.Value,
+		Function:      core.BuiltInFunctionClaimDeveloperRewards,
+	})
+	if err != nil {
+		return &vmcommon.VMOutput{
+			ReturnCode:    vmcommon.UserError,
+			ReturnMessage: err.Error(),
+		}, nil
+	}
+	return vmOutput, nil
+}
@@ -4,20 +4,24 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/multiversx/mx-chain-scenario-go/worldmock"
 )
 
 func (e *Executor) HandleAddress(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	bechAddress := chi.URLParam(r, "address")
 	address, err := bech32Decode(bechAddress)
 	if err != nil {
 		return nil, err
 	}
-	worldAccount := e.getWorldAccount(address)
+	worldAccount := e.lookupWorldAccount(address)
 	withKeys := r.URL.Query().Get("withKeys") == "true"
-	accountData, err := e.getAccountData(worldAccount, withKeys)
+	withEsdts := r.URL.Query().Get("withEsdts") == "true"
+	accountData, err := e.getAccountData(worldAccount, withKeys, withEsdts)
 	if err != nil {
 		return nil, err
 	}
@@ -28,12 +32,14 @@ func (e *Executor) HandleAddress(r *http.Request) (interface{}, error) {
 }
 
 func (e *Executor) HandleAddressNonce(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	bechAddress := chi.URLParam(r, "address")
 	address, err := bech32Decode(bechAddress)
 	if err != nil {
 		return nil, err
 	}
-	worldAccount := e.getWorldAccount(address)
+	worldAccount := e.lookupWorldAccount(address)
 	jData := map[string]interface{}{
 		"nonce": worldAccount.Nonce,
 	}
@@ -41,12 +47,14 @@ func (e *Executor) HandleAddressNonce(r *http.Request) (interface{}, error) {
 }
 
 func (e *Executor) HandleAddressBalance(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	bechAddress := chi.URLParam(r, "address")
 	address, err := bech32Decode(bechAddress)
 	if err != nil {
 		return nil, err
 	}
-	worldAccount := e.getWorldAccount(address)
+	worldAccount := e.lookupWorldAccount(address)
 	jData := map[string]interface{}{
 		"balance": worldAccount.Balance.String(),
 	}
@@ -54,6 +62,8 @@ func (e *Executor) HandleAddressBalance(r *http.Request) (interface{}, error) {
 }
 
 func (e *Executor) HandleAddressKey(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	bechAddress := chi.URLParam(r, "address")
 	address, err := bech32Decode(bechAddress)
 	if err != nil {
@@ -64,7 +74,7 @@ func (e *Executor) HandleAddressKey(r *http.Request) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	worldAccount := e.getWorldAccount(address)
+	worldAccount := e.lookupWorldAccount(address)
 	value := e.getAccountValueData(worldAccount, bytesKey)
 	jData := map[string]interface{}{
 		"value": value,
@@ -73,12 +83,14 @@ func (e *Executor) HandleAddressKey(r *http.Request) (interface{}, error) {
 }
 
 func (e *Executor) HandleAddressKeys(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	bechAddress := chi.URLParam(r, "address")
 	address, err := bech32Decode(bechAddress)
 	if err != nil {
 		return nil, err
 	}
-	worldAccount := e.getWorldAccount(address)
+	worldAccount := e.lookupWorldAccount(address)
 	accountKeysData := e.getAccountKvsData(worldAccount)
 	jData := map[string]interface{}{
 		"pairs": accountKeysData,
@@ -86,6 +98,107 @@ func (e *Executor) HandleAddressKeys(r *http.Request) (interface{}, error) {
 	return jData, nil
 }
 
+func (e *Executor) HandleAddressEsdts(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	bechAddress := chi.URLParam(r, "address")
+	address, err := bech32Decode(bechAddress)
+	if err != nil {
+		return nil, err
+	}
+	worldAccount := e.lookupWorldAccount(address)
+	esdtsData, err := e.getAccountEsdtsData(worldAccount)
+	if err != nil {
+		return nil, err
+	}
+	jData := map[string]interface{}{
+		"esdts": esdtsData,
+	}
+	return jData, nil
+}
+
+func (e *Executor) HandleAddressEsdt(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	bechAddress := chi.URLParam(r, "address")
+	address, err := bech32Decode(bechAddress)
+	if err != nil {
+		return nil, err
+	}
+	tokenIdentifier := chi.URLParam(r, "tokenIdentifier")
+	worldAccount := e.lookupWorldAccount(address)
+	tokenData, err := e.getAccountEsdtData(worldAccount, tokenIdentifier, 0)
+	if err != nil {
+		return nil, err
+	}
+	jData := map[string]interface{}{
+		"tokenData": tokenData,
+	}
+	return jData, nil
+}
+
+func (e *Executor) HandleAddressNft(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	bechAddress := chi.URLParam(r, "address")
+	address, err := bech32Decode(bechAddress)
+	if err != nil {
+		return nil, err
+	}
+	tokenIdentifier := chi.URLParam(r, "tokenIdentifier")
+	nonce, err := strconv.ParseUint(chi.URLParam(r, "nonce"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	worldAccount := e.lookupWorldAccount(address)
+	tokenData, err := e.getAccountEsdtData(worldAccount, tokenIdentifier, nonce)
+	if err != nil {
+		return nil, err
+	}
+	jData := map[string]interface{}{
+		"tokenData": tokenData,
+	}
+	return jData, nil
+}
+
+func (e *Executor) HandleAddressRegisteredNfts(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	bechAddress := chi.URLParam(r, "address")
+	address, err := bech32Decode(bechAddress)
+	if err != nil {
+		return nil, err
+	}
+	worldAccount := e.lookupWorldAccount(address)
+	tokens, err := e.getAccountRegisteredNftsData(worldAccount)
+	if err != nil {
+		return nil, err
+	}
+	jData := map[string]interface{}{
+		"tokens": tokens,
+	}
+	return jData, nil
+}
+
+func (e *Executor) HandleAddressEsdtsRoles(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	bechAddress := chi.URLParam(r, "address")
+	address, err := bech32Decode(bechAddress)
+	if err != nil {
+		return nil, err
+	}
+	worldAccount := e.lookupWorldAccount(address)
+	rolesData, err := e.getAccountRolesData(worldAccount)
+	if err != nil {
+		return nil, err
+	}
+	jData := map[string]interface{}{
+		"roles": rolesData,
+	}
+	return jData, nil
+}
+
 func (e *Executor) getWorldAccount(address []byte) *worldmock.Account {
 	account, ok := e.scenexec.World.AcctMap[string(address)]
 	if ok {
@@ -94,7 +207,15 @@ func (e *Executor) getWorldAccount(address []byte) *worldmock.Account {
 	return e.scenexec.World.AcctMap.CreateAccount(address, e.scenexec.World)
 }
 
-func (e *Executor) getAccountData(worldAccount *worldmock.Account, withKvs bool) (interface{}, error) {
+func (e *Executor) lookupWorldAccount(address []byte) *worldmock.Account {
+	account, ok := e.scenexec.World.AcctMap[string(address)]
+	if ok {
+		return account
+	}
+	return worldmock.NewAccountMap().CreateAccount(address, e.scenexec.World)
+}
+
+func (e *Executor) getAccountData(worldAccount *worldmock.Account, withKvs bool, withEsdts bool) (interface{}, error) {
 	bechAddress, err := bech32Encode(worldAccount.Address)
 	if err != nil {
 		return nil, err
@@ -124,13 +245,32 @@ func (e *Executor) getAccountData(worldAccount *worldmock.Account, withKvs bool)
 		"codeHash":     codeHash,
 		"codeMetadata": codeMetadata,
 		"ownerAddress": bechOwnerAddress,
+		"developerReward": getDeveloperRewardData(worldAccount),
 	}
 	if withKvs {
 		data["pairs"] = e.getAccountKvsData(worldAccount)
 	}
+	if withEsdts {
+		data["esdts"], err = e.getAccountRawEsdtsData(worldAccount)
+		if err != nil {
+			return nil, err
+		}
+		data["roles"], err = e.getAccountRolesData(worldAccount)
+		if err != nil {
+			return nil, err
+		}
+		data["lastNonce"] = getAccountLastNoncesData(worldAccount)
+	}
 	return data, nil
 }
 
+func getDeveloperRewardData(worldAccount *worldmock.Account) string {
+	if worldAccount.DeveloperReward == nil {
+		return "0"
+	}
+	return worldAccount.DeveloperReward.String()
+}
+
 func (e *Executor) getAccountKvsData(worldAccount *worldmock.Account) interface{} {
 	data := map[string]string{}
 	for k, v := range worldAccount.Storage {
@@ -3,19 +3,28 @@ package main
 import (
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"net/http"
+	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/go-chi/chi/v5"
 	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
 	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
 )
 
 func (e *Executor) HandleAdminGetAllAccounts() (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	var accountsData []interface{}
 	for _, worldAccount := range e.scenexec.World.AcctMap {
-		accountData, err := e.getAccountData(worldAccount, true)
+		accountData, err := e.getAccountData(worldAccount, true, false)
 		if err != nil {
 			return nil, err
 		}
@@ -33,6 +42,8 @@ func (e *Executor) HandleAdminGetAllAccounts() (interface{}, error) {
 }
 
 func (e *Executor) HandleAdminSetAccounts(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	reqBody, _ := io.ReadAll(r.Body)
 	var rawAccounts []RawAccount
 	err := json.Unmarshal(reqBody, &rawAccounts)
@@ -50,6 +61,8 @@ func (e *Executor) HandleAdminSetAccounts(r *http.Request) (interface{}, error)
 }
 
 func (e *Executor) HandleAdminUpdateAccounts(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	reqBody, _ := io.ReadAll(r.Body)
 	var rawAccounts []RawAccount
 	err := json.Unmarshal(reqBody, &rawAccounts)
@@ -67,41 +80,169 @@ func (e *Executor) HandleAdminUpdateAccounts(r *http.Request) (interface{}, erro
 }
 
 func (e *Executor) HandleAdminSetCurrentBlockInfo(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	reqBody, _ := io.ReadAll(r.Body)
 	var block Block
 	err := json.Unmarshal(reqBody, &block)
 	if err != nil {
 		return nil, err
 	}
-	e.scenexec.World.CurrentBlockInfo = &worldmock.BlockInfo{
-		BlockTimestamp: block.Timestamp,
-		BlockNonce:     block.Nonce,
-		BlockRound:     block.Round,
-		BlockEpoch:     block.Epoch,
-		RandomSeed:     nil,
+	blockInfo, err := blockToBlockInfo(&block)
+	if err != nil {
+		return nil, err
 	}
+	e.fillRandomSeed(blockInfo)
+	e.scenexec.World.CurrentBlockInfo = blockInfo
 	jData := map[string]interface{}{}
 	return jData, nil
 }
 
 func (e *Executor) HandleAdminSetPreviousBlockInfo(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	reqBody, _ := io.ReadAll(r.Body)
 	var block Block
 	err := json.Unmarshal(reqBody, &block)
 	if err != nil {
 		return nil, err
 	}
-	e.scenexec.World.PreviousBlockInfo = &worldmock.BlockInfo{
-		BlockTimestamp: block.Timestamp,
-		BlockNonce:     block.Nonce,
-		BlockRound:     block.Round,
-		BlockEpoch:     block.Epoch,
-		RandomSeed:     nil,
+	blockInfo, err := blockToBlockInfo(&block)
+	if err != nil {
+		return nil, err
 	}
+	e.fillRandomSeed(blockInfo)
+	e.scenexec.World.PreviousBlockInfo = blockInfo
 	jData := map[string]interface{}{}
 	return jData, nil
 }
 
+func (e *Executor) HandleAdminSnapshot() (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.snapshots) >= e.maxSnapshots {
+		return nil, fmt.Errorf("maximum number of snapshots reached (%d)", e.maxSnapshots)
+	}
+	e.snapshotCounter += 1
+	e.snapshots[e.snapshotCounter] = e.takeSnapshot()
+	jData := map[string]interface{}{
+		"id": e.snapshotCounter,
+	}
+	return jData, nil
+}
+
+func (e *Executor) HandleAdminRevert(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, ok := e.snapshots[id]
+	if !ok {
+		return nil, errors.New("unknown snapshot")
+	}
+	e.restoreSnapshot(snapshot)
+	for otherId := range e.snapshots {
+		if otherId > id {
+			delete(e.snapshots, otherId)
+		}
+	}
+	jData := map[string]interface{}{}
+	return jData, nil
+}
+
+func (e *Executor) HandleAdminDeleteSnapshot(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := e.snapshots[id]; !ok {
+		return nil, errors.New("unknown snapshot")
+	}
+	delete(e.snapshots, id)
+	jData := map[string]interface{}{}
+	return jData, nil
+}
+
+func (e *Executor) HandleAdminSaveState(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	path, err := e.getStateFilePath(r)
+	if err != nil {
+		return nil, err
+	}
+	err = e.saveStateFile(path)
+	if err != nil {
+		return nil, err
+	}
+	jData := map[string]interface{}{
+		"path": path,
+	}
+	return jData, nil
+}
+
+func (e *Executor) HandleAdminLoadState(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	path, err := e.getStateFilePath(r)
+	if err != nil {
+		return nil, err
+	}
+	err = e.loadStateFile(path)
+	if err != nil {
+		return nil, err
+	}
+	jData := map[string]interface{}{
+		"path": path,
+	}
+	return jData, nil
+}
+
+// The API only reads and writes the state file, or the files of the state
+// directory, so that it cannot reach the other files of the machine.
+func (e *Executor) getStateFilePath(r *http.Request) (string, error) {
+	reqBody, _ := io.ReadAll(r.Body)
+	var rawStateFile RawStateFile
+	if len(reqBody) > 0 {
+		err := json.Unmarshal(reqBody, &rawStateFile)
+		if err != nil {
+			return "", err
+		}
+	}
+	if rawStateFile.Path == nil || *rawStateFile.Path == "" {
+		if e.stateFile == "" {
+			return "", errors.New("no state file path provided")
+		}
+		return e.stateFile, nil
+	}
+	path := *rawStateFile.Path
+	if e.stateFile != "" && path == e.stateFile {
+		return path, nil
+	}
+	if e.stateDir == "" {
+		return "", errStateFilePathNotAllowed
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(e.stateDir, path)
+	}
+	path = filepath.Clean(path)
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return "", errStateFilePathNotAllowed
+	}
+	relDir, err := filepath.Rel(e.stateDir, dir)
+	if err != nil || relDir == ".." || strings.HasPrefix(relDir, ".."+string(filepath.Separator)) {
+		return "", errStateFilePathNotAllowed
+	}
+	if fileInfo, err := os.Lstat(path); err == nil && !fileInfo.Mode().IsRegular() {
+		return "", errStateFilePathNotAllowed
+	}
+	return path, nil
+}
+
 func (e *Executor) setAccount(rawAccount RawAccount) error {
 	worldAccount := &worldmock.Account{
 		Nonce:           0,
@@ -158,6 +299,16 @@ func (e *Executor) setAccount(rawAccount RawAccount) error {
 	if err != nil {
 		return err
 	}
+	if rawAccount.DeveloperReward != nil {
+		worldAccount.DeveloperReward, err = stringToBigint(*rawAccount.DeveloperReward)
+		if err != nil {
+			return err
+		}
+	}
+	err = e.setAccountEsdts(worldAccount.Storage, rawAccount)
+	if err != nil {
+		return err
+	}
 	e.scenexec.World.AcctMap.PutAccount(worldAccount)
 	return nil
 }
@@ -223,6 +374,16 @@ func (e *Executor) updateAccount(rawAccount RawAccount) error {
 			worldAccount.OwnerAddress = nil
 		}
 	}
+	if rawAccount.DeveloperReward != nil {
+		worldAccount.DeveloperReward, err = stringToBigint(*rawAccount.DeveloperReward)
+		if err != nil {
+			return err
+		}
+	}
+	err = e.setAccountEsdts(worldAccount.Storage, rawAccount)
+	if err != nil {
+		return err
+	}
 	e.scenexec.World.AcctMap.PutAccount(worldAccount)
 	return nil
 }
@@ -235,6 +396,14 @@ type RawAccount struct {
 	Code 					*string
 	CodeMetadata	*string
 	Owner					*string
+	DeveloperReward	*string
+	Esdts					*[]RawEsdt
+	Roles					*map[string][]string
+	LastNonce			*map[string]uint64
+}
+
+type RawStateFile struct {
+	Path		*string
 }
 
 type Block struct {
@@ -242,4 +411,5 @@ type Block struct {
 	Nonce      uint64
 	Round      uint64
 	Epoch      uint32
+	RandomSeed *string
 }
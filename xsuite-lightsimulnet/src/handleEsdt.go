@@ -0,0 +1,13 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (e *Executor) HandleEsdtProperties(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.getEsdtTokenPropertiesData(chi.URLParam(r, "tokenIdentifier"))
+}
@@ -1,10 +1,24 @@
 package main
 
-func (e *Executor) HandleNetworkStatus() (interface{}, error) {
+import (
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (e *Executor) HandleNetworkStatus(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	shard, err := e.parseShard(chi.URLParam(r, "shard"))
+	if err != nil {
+		return nil, err
+	}
 	jData := map[string]interface{}{
 		"status": map[string]interface{}{
 			"erd_block_timestamp": e.scenexec.World.CurrentTimeStamp(),
-			"erd_cross_check_block_height": "-1",
+			"erd_cross_check_block_height": e.getCrossCheckBlockHeight(shard),
 			"erd_current_round": e.scenexec.World.CurrentRound(),
 			"erd_epoch_number": e.scenexec.World.CurrentEpoch(),
 			"erd_highest_final_nonce": -1,
@@ -13,8 +27,84 @@ func (e *Executor) HandleNetworkStatus() (interface{}, error) {
 			"erd_nonces_passed_in_current_epoch": -1,
 			"erd_round_at_epoch_start": -1,
 			"erd_rounds_passed_in_current_epoch": -1,
-			"erd_rounds_per_epoch": -1,
+			"erd_rounds_per_epoch": e.getRoundsPerEpochData(),
+		},
+	}
+	return jData, nil
+}
+
+func (e *Executor) HandleNetworkConfig() (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	jData := map[string]interface{}{
+		"config": map[string]interface{}{
+			"erd_adaptivity": "false",
+			"erd_chain_id": e.network.ChainID,
+			"erd_denomination": e.network.Denomination,
+			"erd_gas_per_data_byte": e.network.GasPerDataByte,
+			"erd_gas_price_modifier": strconv.FormatFloat(e.network.GasPriceModifier, 'f', -1, 64),
+			"erd_hysteresis": "0.000000",
+			"erd_latest_tag_software_version": "lightsimulnet",
+			"erd_max_gas_per_transaction": e.network.MaxGasPerTransaction,
+			"erd_meta_consensus_group_size": 1,
+			"erd_min_gas_limit": e.network.MinGasLimit,
+			"erd_min_gas_price": e.network.MinGasPrice,
+			"erd_min_transaction_version": e.network.MinTransactionVersion,
+			"erd_num_metachain_nodes": 1,
+			"erd_num_nodes_in_shard": 1,
+			"erd_num_shards_without_meta": e.network.NumShards,
+			"erd_round_duration": e.network.RoundDuration * 1000,
+			"erd_rounds_per_epoch": e.getRoundsPerEpochData(),
+			"erd_shard_consensus_group_size": 1,
+			"erd_start_time": e.network.StartTime,
 		},
 	}
 	return jData, nil
 }
+
+func (e *Executor) HandleNetworkEconomics() (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	totalSupply := big.NewInt(0)
+	for _, account := range e.scenexec.World.AcctMap {
+		totalSupply.Add(totalSupply, account.Balance)
+	}
+	jData := map[string]interface{}{
+		"metrics": map[string]interface{}{
+			"erd_dev_rewards": e.totalDevRewards.String(),
+			"erd_epoch_for_economics_data": e.scenexec.World.CurrentEpoch(),
+			"erd_inflation": "0",
+			"erd_total_base_staked_value": "0",
+			"erd_total_fees": e.totalFees.String(),
+			"erd_total_supply": totalSupply.String(),
+			"erd_total_top_up_value": "0",
+		},
+	}
+	return jData, nil
+}
+
+func (e *Executor) HandleNetworkEsdtSupply(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.getEsdtSupplyData(chi.URLParam(r, "tokenIdentifier"))
+}
+
+func (e *Executor) HandleNetworkEsdts() (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	tokenIdentifiers, err := e.getEsdtTokenIdentifiers()
+	if err != nil {
+		return nil, err
+	}
+	jData := map[string]interface{}{
+		"tokens": tokenIdentifiers,
+	}
+	return jData, nil
+}
+
+func (e *Executor) getRoundsPerEpochData() interface{} {
+	if e.network.RoundsPerEpoch == 0 {
+		return -1
+	}
+	return e.network.RoundsPerEpoch
+}
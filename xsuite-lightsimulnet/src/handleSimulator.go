@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Each block advances the world and drains the mempool under the lock, so a
+// single request is capped. The cap is chosen by the light simulnet.
+const maxNumBlocksToGenerate = 10_000
+
+var errTooManyBlocksToGenerate = errors.New("too many blocks to generate, the maximum is " + strconv.Itoa(maxNumBlocksToGenerate))
+
+func (e *Executor) HandleSimulatorGenerateBlocks(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	numBlocks, err := strconv.ParseUint(chi.URLParam(r, "numBlocks"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if numBlocks > maxNumBlocksToGenerate {
+		return nil, errTooManyBlocksToGenerate
+	}
+	e.generateBlocks(numBlocks)
+	jData := map[string]interface{}{}
+	return jData, nil
+}
@@ -7,26 +7,41 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
-	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-core-go/data/vm"
+	"github.com/multiversx/mx-chain-core-go/marshal"
 	model "github.com/multiversx/mx-chain-scenario-go/scenario/model"
 	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
 )
 
+var txMarshalizer = &marshal.GogoProtoMarshalizer{}
+
 func (e *Executor) HandleTransactionSend(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	reqBody, _ := io.ReadAll(r.Body)
 	var rawTx RawTx
 	err := json.Unmarshal(reqBody, &rawTx)
 	if err != nil {
 		return nil, err
 	}
-	e.txCounter += 1
-	txHash := uint64ToString(e.txCounter)
-	err = e.executeTx(txHash, rawTx)
+	txHash, err := e.getTxHash(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	err = e.checkDuplicatedTx(txHash)
+	if err != nil {
+		return nil, err
+	}
+	e.beforeBatch()
+	err = e.submitTx(txHash, rawTx)
 	if err != nil {
 		return nil, err
 	}
@@ -37,28 +52,204 @@ func (e *Executor) HandleTransactionSend(r *http.Request) (interface{}, error) {
 }
 
 func (e *Executor) HandleTransactionSendMultiple(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	atomicStr := r.URL.Query().Get("atomic")
+	atomic, err := parseBool(atomicStr)
+	if err != nil {
+		return nil, err
+	}
 	reqBody, _ := io.ReadAll(r.Body)
 	var rawTxs []RawTx
-	err := json.Unmarshal(reqBody, &rawTxs)
+	err = json.Unmarshal(reqBody, &rawTxs)
 	if err != nil {
 		return nil, err
 	}
+	// An atomic batch is restored on any way out but its success, a panic
+	// included, so that none of its txs stays committed.
+	committed := !atomic
+	if atomic {
+		snapshot := e.takeSnapshot()
+		defer func() {
+			if !committed {
+				e.restoreSnapshot(snapshot)
+			}
+		}()
+	}
+	e.beforeBatch()
 	txsHashes := make(map[string]string)
+	txsErrors := make(map[string]string)
 	for i, rawTx := range rawTxs {
-		e.txCounter += 1
-		txHash := uint64ToString(e.txCounter)
-		err := e.executeTx(txHash, rawTx)
+		txHash, err := e.getTxHash(rawTx)
+		if err == nil {
+			err = e.checkDuplicatedTx(txHash)
+		}
+		if err == nil {
+			err = e.submitTx(txHash, rawTx)
+		}
 		if err == nil {
 			txsHashes[strconv.Itoa(i)] = txHash
+		} else {
+			txsErrors[strconv.Itoa(i)] = err.Error()
 		}
 	}
+	if atomic && len(txsErrors) > 0 {
+		txsHashes = make(map[string]string)
+	} else {
+		committed = true
+	}
 	jOutput := map[string]interface{}{
+		"numOfSentTxs": len(txsHashes),
 		"txsHashes": txsHashes,
+		"txsErrors": txsErrors,
+	}
+	return jOutput, nil
+}
+
+func (e *Executor) HandleTransactionSimulate(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	checkSignatureStr := r.URL.Query().Get("checkSignature")
+	checkSignature := true
+	if checkSignatureStr != "" {
+		var err error
+		checkSignature, err = parseBool(checkSignatureStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reqBody, _ := io.ReadAll(r.Body)
+	var rawTx RawTx
+	err := json.Unmarshal(reqBody, &rawTx)
+	if err != nil {
+		return nil, err
+	}
+	validation := e.sentTxValidation()
+	validation.verifySignatures = validation.verifySignatures && checkSignature
+	txHash, transaction, err := e.simulateTx(rawTx, validation)
+	if err != nil {
+		return nil, err
+	}
+	status := "success"
+	failReason := ""
+	executionReceipt := transaction["executionReceipt"].(map[string]interface{})
+	if executionReceipt["returnCode"] != vmcommon.Ok {
+		status = "fail"
+		failReason = executionReceipt["returnMessage"].(string)
+	}
+	jOutput := map[string]interface{}{
+		"result": map[string]interface{}{
+			"status": status,
+			"failReason": failReason,
+			"hash": txHash,
+			"scResults": getScrsByHash(transaction["smartContractResults"]),
+			"logs": transaction["logs"],
+			"gasUsed": transaction["gasUsed"],
+			"fee": transaction["fee"],
+		},
+	}
+	return jOutput, nil
+}
+
+func (e *Executor) HandleTransactionCost(r *http.Request) (interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	reqBody, _ := io.ReadAll(r.Body)
+	var rawTx RawTx
+	err := json.Unmarshal(reqBody, &rawTx)
+	if err != nil {
+		return nil, err
+	}
+	// The cost is simulated without fees, so the min gas price is not checked.
+	rawTx.GasLimit = e.network.MaxGasPerTransaction
+	rawTx.GasPrice = 0
+	_, transaction, err := e.simulateTx(rawTx, txValidation{})
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := bech32Decode(rawTx.Receiver)
+	if err != nil {
+		return nil, err
+	}
+	data, err := getRawTxData(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	// The transfers to wallets report no remaining gas, whatever their data, so
+	// their gas is the one of a move balance.
+	txGasUnits := transaction["gasUsed"].(uint64)
+	if len(data) == 0 {
+		txGasUnits = e.network.MinGasLimit
+	} else if e.isMoveBalanceData(receiver, data) {
+		txGasUnits = e.computeMoveBalanceGas(data)
+	}
+	returnMessage := transaction["executionReceipt"].(map[string]interface{})["returnMessage"]
+	jOutput := map[string]interface{}{
+		"txGasUnits": txGasUnits,
+		"returnMessage": returnMessage,
+		"smartContractResults": getScrsByHash(transaction["smartContractResults"]),
+		"logs": transaction["logs"],
+	}
+	return jOutput, nil
+}
+
+// Runs the tx on the world as it is and restores the world right after, so
+// that nothing is committed, including the sender nonce. A tx already sent can
+// be simulated, its response being restored too.
+func (e *Executor) simulateTx(rawTx RawTx, validation txValidation) (string, map[string]interface{}, error) {
+	snapshot := e.takeSnapshot()
+	defer e.restoreSnapshot(snapshot)
+	txHash, err := e.getTxHash(rawTx)
+	if err != nil {
+		return "", nil, err
+	}
+	err = e.executeTxWithValidation(txHash, rawTx, validation)
+	if err != nil {
+		return "", nil, err
+	}
+	txResp := e.txResps[txHash].(map[string]interface{})
+	return txHash, txResp["transaction"].(map[string]interface{}), nil
+}
+
+func getScrsByHash(scrsData interface{}) map[string]interface{} {
+	scrsByHash := map[string]interface{}{}
+	if scrs, ok := scrsData.([]interface{}); ok {
+		for _, scr := range scrs {
+			scrsByHash[scr.(map[string]interface{})["hash"].(string)] = scr
+		}
+	}
+	return scrsByHash
+}
+
+func (e *Executor) HandleTransactionPool(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	regularTransactions := []interface{}{}
+	for _, pending := range e.pendingTxs {
+		regularTransactions = append(regularTransactions, map[string]interface{}{
+			"hash": pending.hash,
+			"nonce": pending.rawTx.Nonce,
+			"sender": pending.rawTx.Sender,
+			"receiver": pending.rawTx.Receiver,
+			"value": pending.rawTx.Value,
+			"gasPrice": pending.rawTx.GasPrice,
+			"gasLimit": pending.rawTx.GasLimit,
+			"data": pending.rawTx.Data,
+		})
+	}
+	jOutput := map[string]interface{}{
+		"txPool": map[string]interface{}{
+			"regularTransactions": regularTransactions,
+			"smartContractResults": []interface{}{},
+			"rewards": []interface{}{},
+		},
 	}
 	return jOutput, nil
 }
 
 func (e *Executor) HandleTransaction(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	txHash := chi.URLParam(r, "txHash")
 	withResultsStr := r.URL.Query().Get("withResults")
 	withResults, err := parseBool(withResultsStr)
@@ -82,134 +273,87 @@ func (e *Executor) HandleTransaction(r *http.Request) (interface{}, error) {
 }
 
 func (e *Executor) HandleTransactionProcessStatus(r *http.Request) (interface{}, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	txHash := chi.URLParam(r, "txHash")
 	res := e.txProcessStatusResps[txHash]
 	return res, nil
 }
 
 func (e *Executor) executeTx(txHash string, rawTx RawTx) (error) {
-	logger := NewLoggerStarted()
-	if rawTx.ChainID != "S" {
-		return errors.New("invalid chain ID")
-	}
-	if rawTx.Version != 1 {
-		return errors.New("invalid version")
+	return e.executeTxWithValidation(txHash, rawTx, e.sentTxValidation())
+}
+
+func (e *Executor) executeTxWithValidation(txHash string, rawTx RawTx, validation txValidation) (error) {
+	err := e.validateTx(rawTx, validation)
+	if err != nil {
+		return err
 	}
-	if rawTx.GasLimit < 50_000 {
-		return errors.New("insufficient gas limit")
+	relayedTx, err := e.parseRelayedTx(rawTx, validation)
+	if err != nil {
+		return err
 	}
-	tx := &model.TxStep{
-		Tx: &model.Transaction{
-			Nonce: model.JSONUint64{Value: rawTx.Nonce},
-			EGLDValue: model.JSONBigIntZero(),
-			GasPrice: model.JSONUint64{Value: rawTx.GasPrice},
-			GasLimit: model.JSONUint64{Value: rawTx.GasLimit},
-		},
+	if relayedTx != nil {
+		return e.executeRelayedTx(txHash, rawTx, relayedTx)
 	}
+	return e.executeUserTx(txHash, rawTx, nil)
+}
+
+// A relayed tx is executed as its inner tx, with the relayer paying the fees.
+func (e *Executor) executeUserTx(txHash string, rawTx RawTx, relayedTx *relayedTx) (error) {
 	sender, err := bech32Decode(rawTx.Sender)
 	if err != nil {
 		return err
 	}
-	tx.Tx.From = model.JSONBytesFromString{Value: sender}
 	senderAccount := e.scenexec.World.AcctMap.GetAccount(sender)
+	if senderAccount == nil {
+		return errAccountNotFound
+	}
 	if senderAccount.Nonce != rawTx.Nonce {
 		return errors.New("invalid nonce")
 	}
-	receiver, err := bech32Decode(rawTx.Receiver)
+	egldValue, err := stringToBigint(rawTx.Value)
 	if err != nil {
 		return err
 	}
-	tx.Tx.To = model.JSONBytesFromString{Value: receiver}
-	egldValue, err := stringToBigint(rawTx.Value)
+	feePayer := sender
+	var relayer []byte
+	if relayedTx != nil {
+		relayer = relayedTx.relayer
+		feePayer = relayer
+		if senderAccount.Balance.Cmp(egldValue) < 0 {
+			return errInsufficientFunds
+		}
+	} else if !e.hasFundsForTx(senderAccount, rawTx, egldValue) {
+		return e.setInsufficientFundsTx(txHash, rawTx, senderAccount)
+	}
+	dataBytes, err := getRawTxData(rawTx)
 	if err != nil {
 		return err
 	}
-	tx.Tx.EGLDValue = model.JSONBigInt{Value: egldValue}
-	if rawTx.Data != nil {
-		dataBytes, err := base64.StdEncoding.DecodeString(*rawTx.Data)
+	moveBalanceGas := e.computeMoveBalanceGas(dataBytes)
+	logger := NewLoggerStarted()
+	defer logger.StopAndCollect()
+	tx := &model.TxStep{
+		Tx: &model.Transaction{
+			Nonce: model.JSONUint64{Value: rawTx.Nonce},
+			EGLDValue: model.JSONBigInt{Value: egldValue},
+			GasPrice: model.JSONUint64{Value: rawTx.GasPrice},
+			GasLimit: model.JSONUint64{Value: rawTx.GasLimit - moveBalanceGas},
+		},
+	}
+	tx.Tx.From = model.JSONBytesFromString{Value: sender}
+	receiver, err := bech32Decode(rawTx.Receiver)
+	if err != nil {
+		return err
+	}
+	tx.Tx.To = model.JSONBytesFromString{Value: receiver}
+	esdtTransferFunction := ""
+	if len(dataBytes) > 0 && !e.isMoveBalanceData(receiver, dataBytes) {
+		esdtTransferFunction, err = parseTxData(tx.Tx, sender, receiver, dataBytes)
 		if err != nil {
 			return err
 		}
-		dataParts := strings.Split(string(dataBytes), "@")
-		i := 0
-		if isAllZero(receiver) {
-			code, err := hex.DecodeString(dataParts[i])
-			if err != nil {
-				return err
-			}
-			tx.Tx.Code = model.JSONBytesFromString{Value: code}
-			i += 2
-			codeMetadata, err := hex.DecodeString(dataParts[i])
-			if err != nil {
-				return err
-			}
-			tx.Tx.CodeMetadata = model.JSONBytesFromString{Value: codeMetadata}
-			i += 1
-		} else {
-			if dataParts[i] == "MultiESDTNFTTransfer" {
-				if !bytes.Equal(sender, receiver) {
-					return errors.New("receiver and sender are not equal")
-				}
-				i += 1
-				realReceiver, err := hex.DecodeString(dataParts[i])
-				if err != nil {
-					return err
-				}
-				tx.Tx.To = model.JSONBytesFromString{Value: realReceiver}
-				i += 1
-				l, err := hexToUint64(dataParts[i])
-				if err != nil {
-					return err
-				}
-				i += 1
-				tx.Tx.ESDTValue = []*model.ESDTTxData{}
-				for j := uint64(0); j < l; j++ {
-					id, err := hex.DecodeString(dataParts[i])
-					if err != nil {
-						return err
-					}
-					i += 1
-					nonce, err := hexToUint64(dataParts[i])
-					if err != nil {
-						return err
-					}
-					i += 1
-					amount, err := hexToBigint(dataParts[i])
-					if err != nil {
-						return err
-					}
-					i += 1
-					tx.Tx.ESDTValue = append(tx.Tx.ESDTValue, &model.ESDTTxData{
-						TokenIdentifier: model.JSONBytesFromString{Value: id},
-						Nonce: model.JSONUint64{Value: nonce},
-						Value: model.JSONBigInt{Value: amount},
-					})
-				}
-				if i < len(dataParts) {
-					function, err := hex.DecodeString(dataParts[i])
-					if err != nil {
-						return err
-					}
-					tx.Tx.Function = string(function)
-					i += 1
-				}
-			} else {
-				if i < len(dataParts) {
-					tx.Tx.Function = dataParts[i]
-					i += 1
-				}
-			}
-		}
-		if i < len(dataParts) {
-			tx.Tx.Arguments = []model.JSONBytesFromTree{}
-			for _, rawArgument := range dataParts[i:] {
-				argument, err := hex.DecodeString(rawArgument)
-				if err != nil {
-					return err
-				}
-				tx.Tx.Arguments = append(tx.Tx.Arguments, model.JSONBytesFromTree{Value: argument})
-			}
-		}
 	}
 	if isAllZero(receiver) {
 		tx.Tx.Type = model.ScDeploy
@@ -218,80 +362,166 @@ func (e *Executor) executeTx(txHash string, rawTx RawTx) (error) {
 	} else {
 		tx.Tx.Type = model.Transfer
 	}
-	if tx.Tx.Type == model.ScDeploy {
+	var newAddress []byte
+	if tx.Tx.Type == model.ScDeploy && e.protocolScAddresses {
+		newAddress = getNewAddressMock(e.scenexec.World, sender, rawTx.Nonce)
+		if newAddress == nil {
+			newAddress = computeContractAddress(sender, rawTx.Nonce, wasmVMType)
+		}
+	} else if tx.Tx.Type == model.ScDeploy {
 		e.scCounter += 1
+		newAddress = uint64ToBytesAddress(e.scCounter, true)
 		e.scenexec.World.NewAddressMocks = append(
 			e.scenexec.World.NewAddressMocks,
 			&worldmock.NewAddressMock{
 				CreatorAddress: tx.Tx.From.Value,
 				CreatorNonce:   tx.Tx.Nonce.Value,
-				NewAddress:     uint64ToBytesAddress(e.scCounter, true),
+				NewAddress:     newAddress,
 			},
 		)
 	}
-	vmOutput, err := e.scenexec.ExecuteTxStep(tx)
+	removeEsdtSystemScAccount := e.placeEsdtSystemScAccount()
+	defer removeEsdtSystemScAccount()
+	if relayedTx != nil {
+		e.advanceRelayedTxGas(relayer, sender, tx.Tx.GasLimit.Value, rawTx.GasPrice)
+	}
+	isClaimDeveloperRewards := tx.Tx.Type == model.ScCall && tx.Tx.Function == core.BuiltInFunctionClaimDeveloperRewards
+	var vmOutput *vmcommon.VMOutput
+	if e.isMultiShard() || isEsdtSystemSc(tx.Tx.To.Value) {
+		vmOutput, err = e.executeShardedTx(txHash, tx.Tx, receiver, dataBytes)
+	} else if isClaimDeveloperRewards {
+		vmOutput, err = e.executeClaimDeveloperRewards(tx.Tx)
+	} else if tx.Tx.Type == model.ScCall && len(tx.Tx.ESDTValue) == 0 && e.isBuiltinFunction(tx.Tx.Function) {
+		// The scenario executor only calls the built-in functions of contracts.
+		vmOutput, err = e.executeShardedTx(txHash, tx.Tx, receiver, dataBytes)
+	} else {
+		vmOutput, err = e.scenexec.ExecuteTxStep(tx)
+		if err != nil {
+			// The scenario executor fails the built-in functions, such as the
+			// transfers of a paused token, once the sender has paid the gas. The
+			// tx is then included as failed, like the node does.
+			vmOutput, err = userErrorOutput(err), nil
+		}
+		if vmOutput.ReturnCode == vmcommon.Ok {
+			e.revertForeignCredits(vmOutput)
+		}
+	}
 	if err != nil {
+		if relayedTx != nil {
+			// The tx is rejected, so the relayer gets back the gas it advanced.
+			e.advanceRelayedTxGas(sender, relayer, tx.Tx.GasLimit.Value, rawTx.GasPrice)
+		}
 		return err
 	}
-	var logs interface{}
+	fees := e.computeTxFees(rawTx, moveBalanceGas, vmOutput.GasRemaining)
+	if relayedTx != nil {
+		fees = e.addRelayerGasToFees(fees, relayedTx.relayerGas, rawTx.GasPrice)
+	}
+	e.settleTxFees(feePayer, tx.Tx.GasLimit.Value, rawTx.GasPrice, fees)
+	if tx.Tx.Type == model.ScCall && !isClaimDeveloperRewards {
+		e.creditDeveloperReward(tx.Tx.To.Value, fees, vmOutput)
+	}
+	if vmOutput.ReturnCode == vmcommon.Ok {
+		err = e.updateEsdtSupplies(vmOutput.Logs)
+		if err != nil {
+			return err
+		}
+	}
+	logEntries := []*vmcommon.LogEntry{}
+	if vmOutput.ReturnCode == vmcommon.Ok && tx.Tx.Type == model.ScCall && len(tx.Tx.ESDTValue) > 0 && !e.isMultiShard() {
+		logEntries = append(logEntries, getEsdtTransferLogEntry(tx.Tx, esdtTransferFunction))
+	}
+	logEntries = append(logEntries, vmOutput.Logs...)
 	var smartContractResults interface{}
+	status := "success"
 	var processStatus string
-	if vmOutput.ReturnCode == 0 {
+	if vmOutput.ReturnCode == vmcommon.Ok {
+		resultSender := tx.Tx.To.Value
+		if tx.Tx.Type == model.ScDeploy {
+			resultSender = newAddress
+			logEntries = append(logEntries, &vmcommon.LogEntry{
+				Identifier: []byte(core.SCDeployIdentifier),
+				Address:    newAddress,
+				Topics:     [][]byte{newAddress, sender, e.scenexec.World.AcctMap.GetAccount(newAddress).CodeHash},
+			})
+		}
+		scrsData, err := e.getOutputTransfersScrsData(txHash, txHash, sender, resultSender, rawTx.GasPrice, vmOutput)
+		if err != nil {
+			return err
+		}
+		numQueuedScrs, err := e.queueOutputTransfers(txHash, txHash, sender, relayer, resultSender, rawTx.GasPrice, vmOutput)
+		if err != nil {
+			return err
+		}
 		jData := "@" + hex.EncodeToString([]byte(vmOutput.ReturnCode.String()))
 		for _, data := range vmOutput.ReturnData {
 			jData += "@" + hex.EncodeToString(data)
 		}
-		if tx.Tx.Type == model.ScDeploy {
-			bechAddress, err := bech32Encode(uint64ToBytesAddress(e.scCounter, true))
+		if fees.refund.Sign() > 0 {
+			scrData, err := e.getScrData(
+				len(scrsData),
+				resultSender,
+				feePayer,
+				sender,
+				fees.refund,
+				jData,
+				0,
+				rawTx.GasPrice,
+				vm.DirectCall,
+				txHash,
+				txHash,
+			)
 			if err != nil {
 				return err
 			}
-			logs = map[string]interface{}{
-				"events": []interface{}{
-					map[string]interface{}{
-						"identifier": "SCDeploy",
-						"address": bechAddress,
-					},
-					map[string]interface{}{
-						"identifier": "writeLog",
-						"data": base64.StdEncoding.EncodeToString([]byte(jData)),
-					},
-				},
-			}
-		} else {
-			logs = map[string]interface{}{
-				"events": []interface{}{
-					map[string]interface{}{
-						"identifier": "completedTxEvent",
-					},
-				},
-			}
-			smartContractResults = []interface{}{
-				map[string]interface{}{
-					"data": jData,
-				},
-			}
+			scrsData = append(scrsData, scrData)
+		} else if tx.Tx.Type != model.Transfer && numQueuedScrs == 0 {
+			logEntries = append(logEntries, &vmcommon.LogEntry{
+				Identifier: []byte(core.WriteLogIdentifier),
+				Address:    sender,
+				Topics:     [][]byte{sender},
+				Data:       [][]byte{[]byte(jData)},
+			})
+		}
+		if tx.Tx.Type != model.ScDeploy && numQueuedScrs == 0 {
+			logEntries = append(logEntries, &vmcommon.LogEntry{
+				Identifier: []byte(core.CompletedTxEventIdentifier),
+				Address:    tx.Tx.To.Value,
+				Topics:     [][]byte{e.txHashBytes(txHash)},
+			})
+		}
+		if len(scrsData) > 0 {
+			smartContractResults = scrsData
 		}
 		processStatus = "success"
-	} else {
-		logs = map[string]interface{}{
-			"events": []interface{}{
-				map[string]interface{}{
-					"identifier": "signalError",
-				},
-			},
+		if numQueuedScrs > 0 {
+			status = "pending"
+			processStatus = "pending"
 		}
+	} else {
+		logEntries = append(logEntries, &vmcommon.LogEntry{
+			Identifier: []byte(core.SignalErrorOperation),
+			Address:    sender,
+			Topics:     [][]byte{tx.Tx.To.Value, []byte(vmOutput.ReturnMessage)},
+			Data:       [][]byte{[]byte("@" + hex.EncodeToString([]byte(vmOutput.ReturnCode.String())))},
+		})
 		processStatus = "failed"
 	}
-	gasUsed := rawTx.GasLimit - vmOutput.GasRemaining
-	fee := new(big.Int).Mul(
-		new(big.Int).SetUint64(gasUsed),
-		new(big.Int).SetUint64(rawTx.GasPrice),
-	)
+	events := []interface{}{}
+	for _, logEntry := range logEntries {
+		event, err := getLogEventData(logEntry)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+	logs := map[string]interface{}{
+		"events": events,
+	}
 	e.txResps[txHash] = map[string]interface{}{
 		"transaction": map[string]interface{}{
 			"hash": txHash,
-			"status": "success",
+			"status": status,
 			"logs": logs,
 			"smartContractResults": smartContractResults,
 			"executionReceipt": map[string]interface{}{
@@ -299,13 +529,181 @@ func (e *Executor) executeTx(txHash string, rawTx RawTx) (error) {
 				"returnMessage": vmOutput.ReturnMessage,
 			},
 			"executionLogs": logger.StopAndCollect(),
-			"gasUsed": rawTx.GasLimit - vmOutput.GasRemaining,
-			"fee": fee.String(),
+			"gasUsed": fees.gasUsed,
+			"initiallyPaidFee": fees.initiallyPaidFee.String(),
+			"fee": fees.fee.String(),
 		},
 	}
 	e.txProcessStatusResps[txHash] = map[string]interface{}{
 		"status": processStatus,
 	}
+	e.keepTx(txHash)
+	if !e.isMultiShard() {
+		e.drainCrossShardScrs()
+	}
+	return nil
+}
+
+// Fills the code, the ESDT transfers, the function and the arguments of the tx
+// from its data, returning the ESDT transfer function called if any.
+func parseTxData(tx *model.Transaction, sender []byte, receiver []byte, dataBytes []byte) (string, error) {
+	esdtTransferFunction := ""
+	dataParts := strings.Split(string(dataBytes), "@")
+	i := 0
+	if isAllZero(receiver) {
+		if i+3 > len(dataParts) {
+			return "", errors.New("invalid deploy data")
+		}
+		code, err := hex.DecodeString(dataParts[i])
+		if err != nil {
+			return "", err
+		}
+		tx.Code = model.JSONBytesFromString{Value: code}
+		i += 2
+		codeMetadata, err := hex.DecodeString(dataParts[i])
+		if err != nil {
+			return "", err
+		}
+		tx.CodeMetadata = model.JSONBytesFromString{Value: codeMetadata}
+		i += 1
+	} else {
+		if dataParts[i] == core.BuiltInFunctionESDTTransfer {
+			esdtTransferFunction = dataParts[i]
+			i += 1
+			if i+2 > len(dataParts) {
+				return "", errors.New("invalid ESDTTransfer data")
+			}
+			id, err := hex.DecodeString(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			i += 1
+			amount, err := hexToBigint(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			i += 1
+			tx.ESDTValue = []*model.ESDTTxData{
+				{
+					TokenIdentifier: model.JSONBytesFromString{Value: id},
+					Nonce: model.JSONUint64{Value: 0},
+					Value: model.JSONBigInt{Value: amount},
+				},
+			}
+		} else if dataParts[i] == core.BuiltInFunctionESDTNFTTransfer {
+			if !bytes.Equal(sender, receiver) {
+				return "", errors.New("receiver and sender are not equal")
+			}
+			esdtTransferFunction = dataParts[i]
+			i += 1
+			if i+4 > len(dataParts) {
+				return "", errors.New("invalid ESDTNFTTransfer data")
+			}
+			id, err := hex.DecodeString(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			i += 1
+			nonce, err := hexToUint64(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			i += 1
+			amount, err := hexToBigint(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			i += 1
+			realReceiver, err := hex.DecodeString(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			tx.To = model.JSONBytesFromString{Value: realReceiver}
+			i += 1
+			tx.ESDTValue = []*model.ESDTTxData{
+				{
+					TokenIdentifier: model.JSONBytesFromString{Value: id},
+					Nonce: model.JSONUint64{Value: nonce},
+					Value: model.JSONBigInt{Value: amount},
+				},
+			}
+		} else if dataParts[i] == core.BuiltInFunctionMultiESDTNFTTransfer {
+			if !bytes.Equal(sender, receiver) {
+				return "", errors.New("receiver and sender are not equal")
+			}
+			esdtTransferFunction = dataParts[i]
+			i += 1
+			if i+2 > len(dataParts) {
+				return "", errors.New("invalid MultiESDTNFTTransfer data")
+			}
+			realReceiver, err := hex.DecodeString(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			tx.To = model.JSONBytesFromString{Value: realReceiver}
+			i += 1
+			l, err := hexToUint64(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			i += 1
+			if l > uint64(len(dataParts)-i)/3 {
+				return "", errors.New("invalid MultiESDTNFTTransfer data")
+			}
+			tx.ESDTValue = []*model.ESDTTxData{}
+			for j := uint64(0); j < l; j++ {
+				id, err := hex.DecodeString(dataParts[i])
+				if err != nil {
+					return "", err
+				}
+				i += 1
+				nonce, err := hexToUint64(dataParts[i])
+				if err != nil {
+					return "", err
+				}
+				i += 1
+				amount, err := hexToBigint(dataParts[i])
+				if err != nil {
+					return "", err
+				}
+				i += 1
+				tx.ESDTValue = append(tx.ESDTValue, &model.ESDTTxData{
+					TokenIdentifier: model.JSONBytesFromString{Value: id},
+					Nonce: model.JSONUint64{Value: nonce},
+					Value: model.JSONBigInt{Value: amount},
+				})
+			}
+		} else {
+			if i < len(dataParts) {
+				tx.Function = dataParts[i]
+				i += 1
+			}
+		}
+		if esdtTransferFunction != "" && i < len(dataParts) {
+			function, err := hex.DecodeString(dataParts[i])
+			if err != nil {
+				return "", err
+			}
+			tx.Function = string(function)
+			i += 1
+		}
+	}
+	if i < len(dataParts) {
+		tx.Arguments = []model.JSONBytesFromTree{}
+		for _, rawArgument := range dataParts[i:] {
+			argument, err := hex.DecodeString(rawArgument)
+			if err != nil {
+				return "", err
+			}
+			tx.Arguments = append(tx.Arguments, model.JSONBytesFromTree{Value: argument})
+		}
+	}
+	return esdtTransferFunction, nil
+}
+
+// Only processed txs are kept, so that the response of a pending tx cannot be
+// evicted before its block is generated.
+func (e *Executor) keepTx(txHash string) {
 	e.hashesOfTxsToKeep = append(e.hashesOfTxsToKeep, txHash)
 	if len(e.hashesOfTxsToKeep) > e.numberOfTxsToKeep {
 		firstTxHash := e.hashesOfTxsToKeep[0]
@@ -313,9 +711,108 @@ func (e *Executor) executeTx(txHash string, rawTx RawTx) (error) {
 		delete(e.txProcessStatusResps, firstTxHash)
 		e.hashesOfTxsToKeep = e.hashesOfTxsToKeep[1:]
 	}
+}
+
+func (e *Executor) getTxHash(rawTx RawTx) (string, error) {
+	if e.counterTxHashes {
+		e.txCounter += 1
+		return uint64ToString(e.txCounter), nil
+	}
+	tx, err := rawTxToTransaction(rawTx)
+	if err != nil {
+		return "", err
+	}
+	txBytes, err := txMarshalizer.Marshal(tx)
+	if err != nil {
+		return "", err
+	}
+	txHash := hex.EncodeToString(worldmock.DefaultHasher.Compute(string(txBytes)))
+	return txHash, nil
+}
+
+// Like the mempool of the node, a tx whose hash is pending or still known is
+// rejected. Replays of older txs are rejected on their nonce, already consumed.
+func (e *Executor) checkDuplicatedTx(txHash string) error {
+	if _, ok := e.txResps[txHash]; ok || e.isPendingTx(txHash) {
+		return errors.New("duplicated transaction")
+	}
 	return nil
 }
 
+func (e *Executor) txHashBytes(txHash string) []byte {
+	if e.counterTxHashes {
+		return []byte(txHash)
+	}
+	txHashBytes, _ := hex.DecodeString(txHash)
+	return txHashBytes
+}
+
+func rawTxToTransaction(rawTx RawTx) (*transaction.Transaction, error) {
+	value, err := stringToBigint(rawTx.Value)
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := bech32Decode(rawTx.Receiver)
+	if err != nil {
+		return nil, errInvalidReceiver
+	}
+	sender, err := bech32Decode(rawTx.Sender)
+	if err != nil {
+		return nil, errInvalidSender
+	}
+	var data []byte
+	if rawTx.Data != nil {
+		data, err = base64.StdEncoding.DecodeString(*rawTx.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	signature, err := hex.DecodeString(rawTx.Signature)
+	if err != nil {
+		return nil, err
+	}
+	var guardian []byte
+	if rawTx.Guardian != "" {
+		guardian, err = bech32Decode(rawTx.Guardian)
+		if err != nil {
+			return nil, err
+		}
+	}
+	guardianSignature, err := hex.DecodeString(rawTx.GuardianSignature)
+	if err != nil {
+		return nil, err
+	}
+	var relayer []byte
+	if rawTx.Relayer != "" {
+		relayer, err = bech32Decode(rawTx.Relayer)
+		if err != nil {
+			return nil, errInvalidRelayer
+		}
+	}
+	relayerSignature, err := hex.DecodeString(rawTx.RelayerSignature)
+	if err != nil {
+		return nil, err
+	}
+	tx := &transaction.Transaction{
+		Nonce:             rawTx.Nonce,
+		Value:             value,
+		RcvAddr:           receiver,
+		SndAddr:           sender,
+		GasPrice:          rawTx.GasPrice,
+		GasLimit:          rawTx.GasLimit,
+		Data:              data,
+		ChainID:           []byte(rawTx.ChainID),
+		Version:           uint32(rawTx.Version),
+		Signature:         signature,
+		Options:           rawTx.Options,
+		GuardianAddr:      guardian,
+		GuardianSignature: guardianSignature,
+		RelayerAddr:       relayer,
+		RelayerSignature:  relayerSignature,
+	}
+	return tx, nil
+}
+
 func isAllZero(bytes []byte) bool {
 	for _, b := range bytes {
 		if b != 0 {
@@ -336,10 +833,22 @@ type RawTx struct {
 	Signature		  string
 	ChainID				string
 	Version				uint64
+	Options				uint32
+	Guardian			string
+	GuardianSignature	string
+	Relayer				string
+	RelayerSignature	string
 }
 
 type RawEsdt struct {
 	Id			string
 	Nonce 	uint64
+	Type		string
 	Amount	string
+	Name		string
+	Creator	string
+	Royalties	uint32
+	Hash		string
+	Attributes	string
+	Uris		[]string
 }
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	model "github.com/multiversx/mx-chain-scenario-go/scenario/model"
+)
+
+func TestTransactionCostWithMinGasPrice(t *testing.T) {
+	network := DefaultNetworkParameters()
+	network.MinGasPrice = 1000000000
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots: 100,
+		Network:      network,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := setTestAccount(t, e, uint64ToBytesAddress(1, false), nil)
+	receiver := setTestAccount(t, e, uint64ToBytesAddress(2, false), nil)
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"receiver": receiver,
+		"sender":   sender,
+		"chainID":  network.ChainID,
+		"version":  1,
+	})
+	res, err := e.HandleTransactionCost(httptest.NewRequest("POST", "/transaction/cost", bytes.NewReader(reqBody)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txGasUnits := res.(map[string]interface{})["txGasUnits"]
+	if txGasUnits != network.MinGasLimit {
+		t.Fatalf("expected %d gas units, got %v", network.MinGasLimit, txGasUnits)
+	}
+	data := base64.StdEncoding.EncodeToString([]byte("hello"))
+	reqBody, _ = json.Marshal(map[string]interface{}{
+		"receiver": receiver,
+		"sender":   sender,
+		"data":     data,
+		"chainID":  network.ChainID,
+		"version":  1,
+	})
+	res, err = e.HandleTransactionCost(httptest.NewRequest("POST", "/transaction/cost", bytes.NewReader(reqBody)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txGasUnits = res.(map[string]interface{})["txGasUnits"]
+	if expected := network.MinGasLimit + 5*network.GasPerDataByte; txGasUnits != expected {
+		t.Fatalf("expected %d gas units, got %v", expected, txGasUnits)
+	}
+	rawTx := newTestTx(t, e, 0)
+	rawTx.GasPrice = 0
+	err = sendTestTx(e, rawTx)
+	if err != errInsufficientGasPrice {
+		t.Fatalf("expected an insufficient gas price, got %v", err)
+	}
+}
+
+func TestParseTxData(t *testing.T) {
+	sender := uint64ToBytesAddress(1, false)
+	contract := uint64ToBytesAddress(2, true)
+	other := uint64ToBytesAddress(3, false)
+	token := hex.EncodeToString([]byte("TOKEN-abcdef"))
+	hexContract := hex.EncodeToString(contract)
+	hexOther := hex.EncodeToString(other)
+	hexSender := hex.EncodeToString(sender)
+	fn := hex.EncodeToString([]byte("fn"))
+	tests := []struct {
+		name      string
+		receiver  []byte
+		data      string
+		err       string
+		to        []byte
+		esdtNonce uint64
+		esdtValue int64
+		function  string
+		arguments []string
+	}{
+		{
+			name:      "ESDTTransfer",
+			receiver:  contract,
+			data:      "ESDTTransfer@" + token + "@0a",
+			to:        contract,
+			esdtValue: 10,
+		},
+		{
+			name:      "ESDTTransfer with function and arguments",
+			receiver:  contract,
+			data:      "ESDTTransfer@" + token + "@0a@" + fn + "@01@02",
+			to:        contract,
+			esdtValue: 10,
+			function:  "fn",
+			arguments: []string{"01", "02"},
+		},
+		{
+			name:      "ESDTNFTTransfer to another receiver",
+			receiver:  sender,
+			data:      "ESDTNFTTransfer@" + token + "@05@0a@" + hexContract + "@" + fn + "@01",
+			to:        contract,
+			esdtNonce: 5,
+			esdtValue: 10,
+			function:  "fn",
+			arguments: []string{"01"},
+		},
+		{
+			name:      "ESDTNFTTransfer to the sender",
+			receiver:  sender,
+			data:      "ESDTNFTTransfer@" + token + "@05@0a@" + hexSender,
+			to:        sender,
+			esdtNonce: 5,
+			esdtValue: 10,
+		},
+		{
+			name:     "ESDTNFTTransfer not sent to the sender",
+			receiver: other,
+			data:     "ESDTNFTTransfer@" + token + "@05@0a@" + hexOther,
+			err:      "receiver and sender are not equal",
+		},
+		{
+			name:     "ESDTTransfer with malformed hex",
+			receiver: contract,
+			data:     "ESDTTransfer@zz@0a",
+			err:      "encoding/hex: invalid byte: U+007A 'z'",
+		},
+		{
+			name:     "ESDTNFTTransfer with malformed hex",
+			receiver: sender,
+			data:     "ESDTNFTTransfer@" + token + "@05@0a@" + hexContract + "@zz",
+			err:      "encoding/hex: invalid byte: U+007A 'z'",
+		},
+		{
+			name:     "ESDTTransfer with missing amount",
+			receiver: contract,
+			data:     "ESDTTransfer@" + token,
+			err:      "invalid ESDTTransfer data",
+		},
+		{
+			name:     "ESDTNFTTransfer with missing amount",
+			receiver: sender,
+			data:     "ESDTNFTTransfer@" + token + "@05",
+			err:      "invalid ESDTNFTTransfer data",
+		},
+		{
+			name:      "ESDTNFTTransfer with zero nonce",
+			receiver:  sender,
+			data:      "ESDTNFTTransfer@" + token + "@@0a@" + hexContract,
+			to:        contract,
+			esdtNonce: 0,
+			esdtValue: 10,
+		},
+		{
+			name:      "MultiESDTNFTTransfer",
+			receiver:  sender,
+			data:      "MultiESDTNFTTransfer@" + hexContract + "@01@" + token + "@05@0a@" + fn,
+			to:        contract,
+			esdtNonce: 5,
+			esdtValue: 10,
+			function:  "fn",
+		},
+		{
+			name:     "MultiESDTNFTTransfer with missing receiver",
+			receiver: sender,
+			data:     "MultiESDTNFTTransfer",
+			err:      "invalid MultiESDTNFTTransfer data",
+		},
+		{
+			name:     "MultiESDTNFTTransfer with missing transfers",
+			receiver: sender,
+			data:     "MultiESDTNFTTransfer@" + hexContract + "@02@" + token + "@05@0a",
+			err:      "invalid MultiESDTNFTTransfer data",
+		},
+		{
+			name:     "MultiESDTNFTTransfer with a huge count",
+			receiver: sender,
+			data:     "MultiESDTNFTTransfer@" + hexContract + "@ffffffffffffffff@" + token + "@05@0a",
+			err:      "invalid MultiESDTNFTTransfer data",
+		},
+		{
+			name:     "Deploy with missing code metadata",
+			receiver: make([]byte, 32),
+			data:     "0061736d@0500",
+			err:      "invalid deploy data",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := &model.Transaction{
+				From: model.JSONBytesFromString{Value: sender},
+				To:   model.JSONBytesFromString{Value: test.receiver},
+			}
+			_, err := parseTxData(tx, sender, test.receiver, []byte(test.data))
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("expected error %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tx.ESDTValue) != 1 {
+				t.Fatalf("expected 1 ESDT transfer, got %d", len(tx.ESDTValue))
+			}
+			esdtValue := tx.ESDTValue[0]
+			if string(esdtValue.TokenIdentifier.Value) != "TOKEN-abcdef" {
+				t.Fatalf("expected token TOKEN-abcdef, got %s", esdtValue.TokenIdentifier.Value)
+			}
+			if esdtValue.Nonce.Value != test.esdtNonce {
+				t.Fatalf("expected nonce %d, got %d", test.esdtNonce, esdtValue.Nonce.Value)
+			}
+			if esdtValue.Value.Value.Int64() != test.esdtValue {
+				t.Fatalf("expected value %d, got %s", test.esdtValue, esdtValue.Value.Value)
+			}
+			if !bytes.Equal(tx.To.Value, test.to) {
+				t.Fatalf("expected receiver %x, got %x", test.to, tx.To.Value)
+			}
+			if tx.Function != test.function {
+				t.Fatalf("expected function %q, got %q", test.function, tx.Function)
+			}
+			arguments := []string{}
+			for _, argument := range tx.Arguments {
+				arguments = append(arguments, hex.EncodeToString(argument.Value))
+			}
+			if strings.Join(arguments, "@") != strings.Join(test.arguments, "@") {
+				t.Fatalf("expected arguments %v, got %v", test.arguments, arguments)
+			}
+		})
+	}
+}
+
+func sendTestTx(e *Executor, rawTx RawTx) error {
+	reqBody, _ := json.Marshal(rawTx)
+	_, err := e.HandleTransactionSend(httptest.NewRequest("POST", "/transaction/send", bytes.NewReader(reqBody)))
+	return err
+}
+
+func newTestTx(t *testing.T, e *Executor, nonce uint64) RawTx {
+	sender, err := bech32Encode(uint64ToBytesAddress(1, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver, err := bech32Encode(uint64ToBytesAddress(2, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return RawTx{
+		Nonce:    nonce,
+		Value:    "1",
+		Receiver: receiver,
+		Sender:   sender,
+		GasPrice: e.network.MinGasPrice,
+		GasLimit: e.network.MinGasLimit,
+		ChainID:  e.network.ChainID,
+		Version:  1,
+	}
+}
+
+func setTestSender(t *testing.T, e *Executor) {
+	sender := setTestAccount(t, e, uint64ToBytesAddress(1, false), nil)
+	balance := "1000000000000000000"
+	err := e.updateAccount(RawAccount{Address: sender, Balance: &balance})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplayedTxsAreRejected(t *testing.T) {
+	e := newTestExecutor(t)
+	e.numberOfTxsToKeep = 2
+	setTestSender(t, e)
+	rawTx := newTestTx(t, e, 0)
+	err := sendTestTx(e, rawTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sendTestTx(e, rawTx)
+	if err == nil || err.Error() != "duplicated transaction" {
+		t.Fatalf("expected a duplicated transaction, got %v", err)
+	}
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		err = sendTestTx(e, newTestTx(t, e, nonce))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The response of the tx is no longer kept, but its nonce is consumed.
+	err = sendTestTx(e, rawTx)
+	if err == nil || err.Error() != "invalid nonce" {
+		t.Fatalf("expected an invalid nonce, got %v", err)
+	}
+}
+
+func TestPendingTxIsSimulated(t *testing.T) {
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots: 100,
+		Mempool:      true,
+		Network:      DefaultNetworkParameters(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	setTestSender(t, e)
+	rawTx := newTestTx(t, e, 0)
+	err = sendTestTx(e, rawTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqBody, _ := json.Marshal(rawTx)
+	res, err := e.HandleTransactionSimulate(httptest.NewRequest("POST", "/transaction/simulate", bytes.NewReader(reqBody)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := res.(map[string]interface{})["result"].(map[string]interface{})["status"]; status != "success" {
+		t.Fatalf("expected a successful simulation, got %v", status)
+	}
+	if len(e.pendingTxs) != 1 {
+		t.Fatalf("expected the tx to stay pending, got %d pending txs", len(e.pendingTxs))
+	}
+}
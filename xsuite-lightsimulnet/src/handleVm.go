@@ -8,19 +8,26 @@ import (
 	"math"
 	"net/http"
 
+	executor "github.com/multiversx/mx-chain-scenario-go/scenario/executor"
 	model "github.com/multiversx/mx-chain-scenario-go/scenario/model"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
 )
 
+// Queries run in parallel, except for the VM execution itself, during which
+// the executionLogs are captured: the logger being global, captures are taken
+// one at a time.
 func (e *Executor) HandleVmQuery(r *http.Request) (interface{}, error) {
-	snapshot := e.scenexec.World.AcctMap.Clone()
-	defer func() {
-		e.scenexec.World.AcctMap = snapshot
-	}()
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	queryScenexec, err := e.getQueryScenexec()
+	if err != nil {
+		return nil, err
+	}
+	defer e.putQueryScenexec(queryScenexec)
 
-	logger := NewLoggerStarted()
 	reqBody, _ := io.ReadAll(r.Body)
 	var rawQuery RawQuery
-	err := json.Unmarshal(reqBody, &rawQuery)
+	err = json.Unmarshal(reqBody, &rawQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +68,10 @@ func (e *Executor) HandleVmQuery(r *http.Request) (interface{}, error) {
 		}
 		tx.Tx.Arguments = append(tx.Tx.Arguments, model.JSONBytesFromTree{Value: argument})
 	}
-	vmOutput, err := e.scenexec.ExecuteTxStep(tx)
+	e.cloneWorldForQuery(queryScenexec.World, e.getShardOf(scAddress))
+	logger := NewLoggerStarted()
+	vmOutput, err := queryScenexec.ExecuteTxStep(tx)
+	executionLogs := logger.StopAndCollect()
 	if err != nil {
 		return nil, err
 	}
@@ -74,12 +84,56 @@ func (e *Executor) HandleVmQuery(r *http.Request) (interface{}, error) {
 			"returnData": b64ReturnData,
 			"returnCode": vmOutput.ReturnCode,
 			"returnMessage": vmOutput.ReturnMessage,
-			"executionLogs": logger.StopAndCollect(),
+			"executionLogs": executionLogs,
 		},
 	}
 	return jOutput, nil
 }
 
+// Queries run on their own scenario executors, each with its own VM, against a
+// clone of the world, so that they only wait for each other while capturing
+// their logs.
+func (e *Executor) getQueryScenexec() (*executor.ScenarioExecutor, error) {
+	e.queryScenexecsMu.Lock()
+	if n := len(e.queryScenexecs); n > 0 {
+		queryScenexec := e.queryScenexecs[n-1]
+		e.queryScenexecs = e.queryScenexecs[:n-1]
+		e.queryScenexecsMu.Unlock()
+		return queryScenexec, nil
+	}
+	e.queryScenexecsMu.Unlock()
+	var queryScenexec *executor.ScenarioExecutor
+	var err error
+	withoutCapture(func() {
+		queryScenexec, err = newScenexec(e.protocolScAddresses)
+	})
+	return queryScenexec, err
+}
+
+func (e *Executor) putQueryScenexec(queryScenexec *executor.ScenarioExecutor) {
+	queryScenexec.World.AcctMap = worldmock.NewAccountMap()
+	e.queryScenexecsMu.Lock()
+	defer e.queryScenexecsMu.Unlock()
+	e.queryScenexecs = append(e.queryScenexecs, queryScenexec)
+}
+
+func (e *Executor) cloneWorldForQuery(queryWorld *worldmock.MockWorld, shard uint32) {
+	world := e.scenexec.World
+	queryWorld.AcctMap = world.AcctMap.Clone()
+	for _, account := range queryWorld.AcctMap {
+		account.MockWorld = queryWorld
+		if e.isMultiShard() {
+			account.ShardID = e.getShardOf(account.Address)
+		}
+	}
+	queryWorld.CurrentBlockInfo = world.CurrentBlockInfo
+	queryWorld.PreviousBlockInfo = world.PreviousBlockInfo
+	queryWorld.SelfShardID = world.SelfShardID
+	if e.isMultiShard() {
+		queryWorld.SelfShardID = shard
+	}
+}
+
 type RawQuery struct {
 	ScAddress		string
 	FuncName		string
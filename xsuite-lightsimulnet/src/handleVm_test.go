@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const worldWasmPath = "../../xsuite/contracts/output-reproducible/world/world.wasm"
+
+func newTestExecutor(t *testing.T) *Executor {
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots: 100,
+		Network:      DefaultNetworkParameters(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func setTestAccount(t *testing.T, e *Executor, address []byte, code []byte) string {
+	bechAddress, err := bech32Encode(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hexCode := hex.EncodeToString(code)
+	err = e.setAccount(RawAccount{Address: bechAddress, Code: &hexCode})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bechAddress
+}
+
+func queryCaller(e *Executor, contract string, caller string) (string, string, error) {
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"scAddress": contract,
+		"funcName":  "get_caller",
+		"caller":    caller,
+	})
+	res, err := e.HandleVmQuery(httptest.NewRequest("POST", "/vm-values/query", bytes.NewReader(reqBody)))
+	if err != nil {
+		return "", "", err
+	}
+	data := res.(map[string]interface{})["data"].(map[string]interface{})
+	caller, err = bech32EncodeBase64(data["returnData"].([]string)[0])
+	if err != nil {
+		return "", "", err
+	}
+	return caller, data["executionLogs"].(string), nil
+}
+
+func bech32EncodeBase64(value string) (string, error) {
+	address, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	return bech32Encode(address)
+}
+
+// The queries must not wait for a reader holding the executor, and must each
+// see their own caller, in their result and in their execution logs.
+func TestVmQueriesRunInParallel(t *testing.T) {
+	code, err := os.ReadFile(worldWasmPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newTestExecutor(t)
+	contract := setTestAccount(t, e, uint64ToBytesAddress(1, true), code)
+	callers := []string{}
+	for i := uint64(1); i <= 8; i++ {
+		callers = append(callers, setTestAccount(t, e, uint64ToBytesAddress(i, false), nil))
+	}
+
+	e.mu.RLock()
+	var wg sync.WaitGroup
+	errs := make(chan error, len(callers)*4)
+	for _, caller := range callers {
+		for j := 0; j < 4; j++ {
+			wg.Add(1)
+			go func(caller string) {
+				defer wg.Done()
+				returnedCaller, executionLogs, err := queryCaller(e, contract, caller)
+				if err == nil && returnedCaller != caller {
+					err = fmt.Errorf("expected caller %s, got %s", caller, returnedCaller)
+				}
+				if err == nil {
+					err = checkQueryLogs(executionLogs, caller, callers)
+				}
+				errs <- err
+			}(caller)
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		t.Fatal("queries blocked while the executor was held for reading")
+	}
+	e.mu.RUnlock()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The world the queries ran on is left untouched.
+	for _, caller := range callers {
+		account, err := bech32Decode(caller)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nonce := e.scenexec.World.AcctMap.GetAccount(account).Nonce; nonce != 0 {
+			t.Fatalf("caller nonce changed to %d", nonce)
+		}
+	}
+}
+
+func checkQueryLogs(executionLogs string, caller string, callers []string) error {
+	for _, otherCaller := range callers {
+		address, err := bech32Decode(otherCaller)
+		if err != nil {
+			return err
+		}
+		logged := strings.Contains(executionLogs, "caller = "+hex.EncodeToString(address))
+		if logged != (otherCaller == caller) {
+			return fmt.Errorf("execution logs of caller %s mismatch caller %s", caller, otherCaller)
+		}
+	}
+	return nil
+}
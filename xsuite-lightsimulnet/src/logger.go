@@ -2,12 +2,20 @@ package main
 
 import (
 	"bytes"
+	"sync"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 )
 
+// The logger is global and the VM logs from its own goroutines, so lines cannot
+// be told apart by caller. A started logger thus holds captureMu until it stops,
+// so that each capture only contains the lines of its own execution.
+var captureMu sync.Mutex
+
 type Logger struct {
-	buf bytes.Buffer
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	started bool
 }
 
 func NewLoggerStarted() *Logger {
@@ -16,15 +24,39 @@ func NewLoggerStarted() *Logger {
 	return l
 }
 
+func (obj *Logger) Write(p []byte) (int, error) {
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
+	return obj.buf.Write(p)
+}
+
 func (obj *Logger) Start() {
+	captureMu.Lock()
+	obj.started = true
 	_ = logger.SetLogLevel("*:TRACE")
 	logger.ToggleCorrelation(false)
 	logger.ToggleLoggerName(true)
 	logger.ClearLogObservers()
-	logger.AddLogObserver(&obj.buf, &logger.PlainFormatter{})
+	_ = logger.AddLogObserver(obj, &logger.PlainFormatter{})
 }
 
+// Stopping again is a no-op, so that a deferred stop covers the early returns.
 func (obj *Logger) StopAndCollect() string {
-	_ = logger.SetLogLevel("*:NONE")
+	if obj.started {
+		_ = logger.SetLogLevel("*:NONE")
+		logger.ClearLogObservers()
+		obj.started = false
+		captureMu.Unlock()
+	}
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
 	return obj.buf.String()
 }
+
+// Runs code logging outside of the executions, such as the creation of a VM,
+// without its lines ending up in a running capture.
+func withoutCapture(f func()) {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	f()
+}
@@ -12,105 +12,288 @@ import (
 
 func main() {
 	port := flag.Int("server-port", 8085, "Port to start the server on (default: 8085)")
+	maxSnapshots := flag.Int("max-snapshots", 100, "Maximum number of snapshots kept at once (default: 100)")
+	stateFile := flag.String("state-file", "", "File used by /admin/save-state and /admin/load-state when no path is given")
+	stateDir := flag.String("state-dir", "", "Directory whose files /admin/save-state and /admin/load-state may use, given by path")
+	loadState := flag.String("load-state", "", "State file to load on start-up")
+	counterTxHashes := flag.Bool("counter-tx-hashes", false, "Use an incrementing counter as tx hash instead of the protocol hash")
+	verifySignatures := flag.Bool("verify-signatures", false, "Reject transactions whose Ed25519 signature is invalid")
+	mempool := flag.Bool("mempool", false, "Keep sent transactions pending until blocks are generated")
+	autoAdvance := flag.String("auto-advance", "", "Advance the current block before each tx (\"tx\") or each sent batch (\"batch\")")
+	roundDuration := flag.Uint64("round-duration", 6, "Seconds added to the block timestamp at each round (default: 6)")
+	roundsPerEpoch := flag.Uint64("rounds-per-epoch", 0, "Rounds after which the epoch is incremented, 0 to never change it")
+	randomSeed := flag.String("random-seed", "", "Hex master seed from which a random seed is derived for each block")
+	accumulateFees := flag.Bool("accumulate-fees", false, "Track the fees and developer rewards paid in /network/economics")
+	protocolScAddresses := flag.Bool("protocol-sc-addresses", false, "Derive contract addresses from the creator address and nonce like the protocol")
+	configFile := flag.String("config", "", "TOML network profile, overridden by the network flags given explicitly")
+	chainID := flag.String("chain-id", "S", "Chain ID accepted in transactions (default: S)")
+	minTxVersion := flag.Uint("min-tx-version", 1, "Lowest accepted transaction version (default: 1)")
+	maxTxVersion := flag.Uint("max-tx-version", 0, "Highest accepted transaction version, 0 for no limit")
+	addressHrp := flag.String("address-hrp", "erd", "Human-readable part of bech32 addresses (default: erd)")
+	minGasLimit := flag.Uint64("min-gas-limit", 50_000, "Minimum gas limit of a transaction (default: 50000)")
+	gasPerDataByte := flag.Uint64("gas-per-data-byte", 1_500, "Gas charged per byte of transaction data (default: 1500)")
+	minGasPrice := flag.Uint64("min-gas-price", 0, "Minimum gas price of a transaction (default: 0)")
+	maxGasPerTx := flag.Uint64("max-gas-per-tx", 600_000_000, "Maximum gas limit of a transaction (default: 600000000)")
+	maxGasPerBlock := flag.Uint64("max-gas-per-block", 1_500_000_000, "Maximum sum of the gas limits of the transactions of a generated block (default: 1500000000)")
+	numShards := flag.Uint("num-shards", 1, "Number of shards, cross-shard steps being executed at the next blocks (default: 1)")
+	esdtIssueCost := flag.String("esdt-issue-cost", "50000000000000000", "EGLD value to send when issuing an ESDT token (default: 50000000000000000)")
 	flag.Parse()
 
+	network := DefaultNetworkParameters()
+	if *configFile != "" {
+		profileNetwork, err := LoadNetworkParameters(*configFile)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to load network profile: %s", err))
+		}
+		network = profileNetwork
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "round-duration":
+			network.RoundDuration = *roundDuration
+		case "rounds-per-epoch":
+			network.RoundsPerEpoch = *roundsPerEpoch
+		case "chain-id":
+			network.ChainID = *chainID
+		case "min-tx-version":
+			network.MinTransactionVersion = uint32(*minTxVersion)
+		case "max-tx-version":
+			network.MaxTransactionVersion = uint32(*maxTxVersion)
+		case "address-hrp":
+			network.AddressHrp = *addressHrp
+		case "min-gas-limit":
+			network.MinGasLimit = *minGasLimit
+		case "gas-per-data-byte":
+			network.GasPerDataByte = *gasPerDataByte
+		case "min-gas-price":
+			network.MinGasPrice = *minGasPrice
+		case "max-gas-per-tx":
+			network.MaxGasPerTransaction = *maxGasPerTx
+		case "max-gas-per-block":
+			network.MaxGasPerBlock = *maxGasPerBlock
+		case "num-shards":
+			network.NumShards = uint32(*numShards)
+		case "esdt-issue-cost":
+			network.EsdtIssueCost = *esdtIssueCost
+		}
+	})
+
 	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", *port))
 	if err != nil {
 		panic(err)
 	}
 	defer listener.Close()
 
-	executor, err := NewExecutor()
+	executor, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots: *maxSnapshots,
+		StateFile: *stateFile,
+		StateDir: *stateDir,
+		CounterTxHashes: *counterTxHashes,
+		VerifySignatures: *verifySignatures,
+		Mempool: *mempool,
+		AutoAdvance: *autoAdvance,
+		Network: network,
+		RandomSeed: *randomSeed,
+		AccumulateFees: *accumulateFees,
+		ProtocolScAddresses: *protocolScAddresses,
+	})
 	if err != nil {
-		panic("Failed to instantiate Executor")
+		panic(fmt.Sprintf("Failed to instantiate Executor: %s", err))
+	}
+	if *loadState != "" {
+		err = executor.loadStateFile(*loadState)
+		if err != nil {
+			panic(err)
+		}
 	}
 
+	router := newRouter(executor)
+
+	fmt.Printf("Server running on http://%s\n", listener.Addr().String())
+	if err := http.Serve(listener, router); err != nil {
+		panic(err)
+	}
+}
+
+func newRouter(e *Executor) *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Get("/address/{address}", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAddress(r)
+		data, err := e.HandleAddress(r)
 		respond(w, data, err)
 	})
 
 	router.Get("/address/{address}/nonce", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAddressNonce(r)
+		data, err := e.HandleAddressNonce(r)
 		respond(w, data, err)
 	})
 
 	router.Get("/address/{address}/balance", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAddressBalance(r)
+		data, err := e.HandleAddressBalance(r)
 		respond(w, data, err)
 	})
 
 	router.Get("/address/{address}/keys", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAddressKeys(r)
+		data, err := e.HandleAddressKeys(r)
 		respond(w, data, err)
 	})
 
 	router.Get("/address/{address}/key/{key}", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAddressKey(r)
+		data, err := e.HandleAddressKey(r)
+		respond(w, data, err)
+	})
+
+	router.Get("/address/{address}/esdt", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAddressEsdts(r)
+		respond(w, data, err)
+	})
+
+	router.Get("/address/{address}/esdt/{tokenIdentifier}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAddressEsdt(r)
+		respond(w, data, err)
+	})
+
+	router.Get("/address/{address}/nft/{tokenIdentifier}/nonce/{nonce}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAddressNft(r)
+		respond(w, data, err)
+	})
+
+	router.Get("/address/{address}/registered-nfts", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAddressRegisteredNfts(r)
+		respond(w, data, err)
+	})
+
+	router.Get("/address/{address}/esdts/roles", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAddressEsdtsRoles(r)
 		respond(w, data, err)
 	})
 
 	router.Post("/transaction/send", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleTransactionSend(r)
+		data, err := e.HandleTransactionSend(r)
 		respond(w, data, err)
 	})
 
 	router.Post("/transaction/send-multiple", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleTransactionSendMultiple(r)
+		data, err := e.HandleTransactionSendMultiple(r)
+		respond(w, data, err)
+	})
+
+	router.Get("/transaction/pool", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleTransactionPool(r)
+		respond(w, data, err)
+	})
+
+	router.Post("/transaction/simulate", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleTransactionSimulate(r)
+		respond(w, data, err)
+	})
+
+	router.Post("/transaction/cost", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleTransactionCost(r)
 		respond(w, data, err)
 	})
 
 	router.Get("/transaction/{txHash}", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleTransaction(r)
+		data, err := e.HandleTransaction(r)
 		respond(w, data, err)
 	})
 
 	router.Get("/transaction/{txHash}/process-status", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleTransactionProcessStatus(r)
+		data, err := e.HandleTransactionProcessStatus(r)
 		respond(w, data, err)
 	})
 
 	router.Post("/vm-values/query", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleVmQuery(r)
+		data, err := e.HandleVmQuery(r)
 		respond(w, data, err)
 	})
 
 	router.Get("/admin/get-all-accounts", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAdminGetAllAccounts()
+		data, err := e.HandleAdminGetAllAccounts()
 		respond(w, data, err)
 	})
 
 	router.Post("/admin/set-accounts", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAdminSetAccounts(r)
+		data, err := e.HandleAdminSetAccounts(r)
 		respond(w, data, err)
 	})
 
 	router.Post("/admin/update-accounts", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAdminUpdateAccounts(r)
+		data, err := e.HandleAdminUpdateAccounts(r)
 		respond(w, data, err)
 	})
 
 	router.Post("/admin/set-current-block-info", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAdminSetCurrentBlockInfo(r)
+		data, err := e.HandleAdminSetCurrentBlockInfo(r)
 		respond(w, data, err)
 	})
 
 	router.Post("/admin/set-previous-block-info", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleAdminSetPreviousBlockInfo(r)
+		data, err := e.HandleAdminSetPreviousBlockInfo(r)
+		respond(w, data, err)
+	})
+
+	router.Post("/admin/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAdminSnapshot()
+		respond(w, data, err)
+	})
+
+	router.Post("/admin/revert/{id}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAdminRevert(r)
+		respond(w, data, err)
+	})
+
+	router.Post("/admin/delete-snapshot/{id}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAdminDeleteSnapshot(r)
+		respond(w, data, err)
+	})
+
+	router.Post("/admin/save-state", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAdminSaveState(r)
+		respond(w, data, err)
+	})
+
+	router.Post("/admin/load-state", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleAdminLoadState(r)
 		respond(w, data, err)
 	})
 
 	router.Get("/network/status/{shard}", func(w http.ResponseWriter, r *http.Request) {
-		data, err := executor.HandleNetworkStatus()
+		data, err := e.HandleNetworkStatus(r)
 		respond(w, data, err)
 	})
 
-	fmt.Printf("Server running on http://%s\n", listener.Addr().String())
-	if err := http.Serve(listener, router); err != nil {
-		panic(err)
-	}
+	router.Get("/network/config", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleNetworkConfig()
+		respond(w, data, err)
+	})
+
+	router.Get("/network/economics", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleNetworkEconomics()
+		respond(w, data, err)
+	})
+
+	router.Get("/network/esdt/supply/{tokenIdentifier}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleNetworkEsdtSupply(r)
+		respond(w, data, err)
+	})
+
+	router.Get("/network/esdts", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleNetworkEsdts()
+		respond(w, data, err)
+	})
+
+	router.Get("/esdt/{tokenIdentifier}/properties", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleEsdtProperties(r)
+		respond(w, data, err)
+	})
+
+	router.Post("/simulator/generate-blocks/{numBlocks}", func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.HandleSimulatorGenerateBlocks(r)
+		respond(w, data, err)
+	})
+
+	return router
 }
 
 func respond(w http.ResponseWriter, data interface{}, err error) {
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func requestJson(method string, url string, body interface{}) (map[string]interface{}, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var resBody map[string]interface{}
+	err = json.NewDecoder(res.Body).Decode(&resBody)
+	if err != nil {
+		return nil, err
+	}
+	if resBody["code"] != "successful" {
+		return nil, fmt.Errorf("%s %s: %v", method, url, resBody["error"])
+	}
+	return resBody["data"].(map[string]interface{}), nil
+}
+
+// Txs sent in parallel must all be executed one after the other, while the
+// accounts are read and the contract queried at the same time. Run it with
+// the race detector through `pnpm test-go`.
+func TestConcurrentSendsReadsAndQueries(t *testing.T) {
+	code, err := os.ReadFile(worldWasmPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newTestExecutor(t)
+	server := httptest.NewServer(newRouter(e))
+	defer server.Close()
+	contract := setTestAccount(t, e, uint64ToBytesAddress(1, true), code)
+	senders := []string{}
+	balance := "1000000000000000000"
+	for i := uint64(1); i <= 4; i++ {
+		sender := setTestAccount(t, e, uint64ToBytesAddress(i, false), nil)
+		err = e.updateAccount(RawAccount{Address: sender, Balance: &balance})
+		if err != nil {
+			t.Fatal(err)
+		}
+		senders = append(senders, sender)
+	}
+	numTxsPerSender := uint64(10)
+	fundData := base64.StdEncoding.EncodeToString([]byte("fund"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(senders)*3)
+	for _, sender := range senders {
+		wg.Add(3)
+		go func(sender string) {
+			defer wg.Done()
+			for nonce := uint64(0); nonce < numTxsPerSender; nonce++ {
+				_, err := requestJson("POST", server.URL+"/transaction/send", map[string]interface{}{
+					"nonce":    nonce,
+					"value":    "1",
+					"receiver": contract,
+					"sender":   sender,
+					"gasPrice": e.network.MinGasPrice,
+					"gasLimit": 10_000_000,
+					"data":     fundData,
+					"chainID":  e.network.ChainID,
+					"version":  1,
+				})
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}(sender)
+		go func(sender string) {
+			defer wg.Done()
+			lastNonce := float64(0)
+			for i := uint64(0); i < numTxsPerSender; i++ {
+				data, err := requestJson("GET", server.URL+"/address/"+sender, nil)
+				if err != nil {
+					errs <- err
+					return
+				}
+				nonce := data["account"].(map[string]interface{})["nonce"].(float64)
+				if nonce < lastNonce {
+					errs <- fmt.Errorf("nonce of %s went back from %v to %v", sender, lastNonce, nonce)
+					return
+				}
+				lastNonce = nonce
+			}
+			errs <- nil
+		}(sender)
+		go func(sender string) {
+			defer wg.Done()
+			for i := uint64(0); i < numTxsPerSender; i++ {
+				data, err := requestJson("POST", server.URL+"/vm-values/query", map[string]interface{}{
+					"scAddress": contract,
+					"funcName":  "get_caller",
+					"caller":    sender,
+				})
+				if err != nil {
+					errs <- err
+					return
+				}
+				returnData := data["data"].(map[string]interface{})["returnData"].([]interface{})
+				caller, err := bech32EncodeBase64(returnData[0].(string))
+				if err == nil && caller != sender {
+					err = fmt.Errorf("expected caller %s, got %s", sender, caller)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}(sender)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, sender := range senders {
+		data, err := requestJson("GET", server.URL+"/address/"+sender+"/nonce", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nonce := uint64(data["nonce"].(float64)); nonce != numTxsPerSender {
+			t.Fatalf("expected nonce %d for %s, got %d", numTxsPerSender, sender, nonce)
+		}
+	}
+	data, err := requestJson("GET", server.URL+"/address/"+contract+"/balance", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numTxs := len(senders) * int(numTxsPerSender)
+	if data["balance"] != strconv.Itoa(numTxs) {
+		t.Fatalf("expected contract balance %d, got %v", numTxs, data["balance"])
+	}
+	if len(e.txResps) != numTxs {
+		t.Fatalf("expected %d tx responses, got %d", numTxs, len(e.txResps))
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+)
+
+type pendingTx struct {
+	hash  string
+	rawTx RawTx
+}
+
+func (e *Executor) submitTx(txHash string, rawTx RawTx) error {
+	if e.mempool {
+		return e.addPendingTx(txHash, rawTx)
+	}
+	if e.autoAdvance == autoAdvanceTx {
+		e.advanceBlock()
+	}
+	return e.executeTx(txHash, rawTx)
+}
+
+func (e *Executor) addPendingTx(txHash string, rawTx RawTx) error {
+	err := e.validateTx(rawTx, e.sentTxValidation())
+	if err != nil {
+		return err
+	}
+	sender, err := bech32Decode(rawTx.Sender)
+	if err != nil {
+		return err
+	}
+	if rawTx.Nonce < e.lookupWorldAccount(sender).Nonce {
+		return errors.New("invalid nonce")
+	}
+	e.pendingTxs = append(e.pendingTxs, pendingTx{hash: txHash, rawTx: rawTx})
+	e.txResps[txHash] = map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"hash":   txHash,
+			"status": "pending",
+		},
+	}
+	e.txProcessStatusResps[txHash] = map[string]interface{}{
+		"status": "pending",
+	}
+	return nil
+}
+
+func (e *Executor) isPendingTx(txHash string) bool {
+	for _, pending := range e.pendingTxs {
+		if pending.hash == txHash {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Executor) generateBlocks(numBlocks uint64) {
+	for i := uint64(0); i < numBlocks; i++ {
+		e.advanceBlock()
+		e.processPendingTxs()
+	}
+}
+
+// Txs are processed while one of them has the nonce expected for its sender,
+// the highest gas price first, and fits in the gas left in the block. Txs with
+// a nonce gap or not fitting stay in the pool for the next blocks.
+func (e *Executor) processPendingTxs() {
+	blockGas := uint64(0)
+	for {
+		next := -1
+		for i, pending := range e.pendingTxs {
+			sender, err := bech32Decode(pending.rawTx.Sender)
+			if err != nil || pending.rawTx.Nonce != e.lookupWorldAccount(sender).Nonce {
+				continue
+			}
+			if next == -1 || pending.rawTx.GasPrice > e.pendingTxs[next].rawTx.GasPrice {
+				next = i
+			}
+		}
+		if next == -1 || blockGas+e.pendingTxs[next].rawTx.GasLimit > e.network.MaxGasPerBlock {
+			break
+		}
+		pending := e.pendingTxs[next]
+		blockGas += pending.rawTx.GasLimit
+		e.pendingTxs = append(e.pendingTxs[:next:next], e.pendingTxs[next+1:]...)
+		err := e.executeTx(pending.hash, pending.rawTx)
+		if err != nil {
+			e.setInvalidTx(pending.hash, err)
+		}
+	}
+	remainingTxs := []pendingTx{}
+	for _, pending := range e.pendingTxs {
+		sender, err := bech32Decode(pending.rawTx.Sender)
+		if err != nil || pending.rawTx.Nonce < e.lookupWorldAccount(sender).Nonce {
+			e.setInvalidTx(pending.hash, errors.New("invalid nonce"))
+			continue
+		}
+		remainingTxs = append(remainingTxs, pending)
+	}
+	e.pendingTxs = remainingTxs
+}
+
+func (e *Executor) setInvalidTx(txHash string, err error) {
+	e.txResps[txHash] = map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"hash":   txHash,
+			"status": "invalid",
+			"receipt": map[string]interface{}{
+				"data": err.Error(),
+			},
+		},
+	}
+	e.txProcessStatusResps[txHash] = map[string]interface{}{
+		"status": "invalid",
+	}
+	e.keepTx(txHash)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPendingTxsAreNotEvicted(t *testing.T) {
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots: 100,
+		Mempool:      true,
+		Network:      DefaultNetworkParameters(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.numberOfTxsToKeep = 2
+	sender := setTestAccount(t, e, uint64ToBytesAddress(1, false), nil)
+	balance := "1000000000000000000"
+	err = e.updateAccount(RawAccount{Address: sender, Balance: &balance})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver := setTestAccount(t, e, uint64ToBytesAddress(2, false), nil)
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		err = e.submitTx(strconv.FormatUint(nonce, 10), RawTx{
+			Nonce:    nonce,
+			Value:    "1",
+			Receiver: receiver,
+			Sender:   sender,
+			GasPrice: e.network.MinGasPrice,
+			GasLimit: e.network.MinGasLimit,
+			ChainID:  e.network.ChainID,
+			Version:  1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(e.txResps) != 3 || len(e.hashesOfTxsToKeep) != 0 {
+		t.Fatalf("expected the 3 pending txs to be kept, got %d", len(e.txResps))
+	}
+	err = e.checkDuplicatedTx("0")
+	if err == nil || err.Error() != "duplicated transaction" {
+		t.Fatalf("expected a duplicated transaction, got %v", err)
+	}
+
+	e.generateBlocks(1)
+	if len(e.pendingTxs) != 0 {
+		t.Fatalf("expected no pending tx, got %d", len(e.pendingTxs))
+	}
+	if len(e.hashesOfTxsToKeep) != 2 || e.hashesOfTxsToKeep[0] != "1" || e.hashesOfTxsToKeep[1] != "2" {
+		t.Fatalf("expected the kept txs 1 and 2, got %v", e.hashesOfTxsToKeep)
+	}
+	if len(e.txResps) != 2 {
+		t.Fatalf("expected 2 tx responses, got %d", len(e.txResps))
+	}
+}
+
+func TestBlocksAreFilledUpToTheirMaxGas(t *testing.T) {
+	network := DefaultNetworkParameters()
+	network.MaxGasPerTransaction = network.MinGasLimit
+	network.MaxGasPerBlock = 2 * network.MinGasLimit
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots: 100,
+		Mempool:      true,
+		Network:      network,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	setTestSender(t, e)
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		err = e.submitTx(strconv.FormatUint(nonce, 10), newTestTx(t, e, nonce))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	e.generateBlocks(1)
+	if len(e.pendingTxs) != 1 || e.pendingTxs[0].hash != "2" {
+		t.Fatalf("expected the tx 2 to be left for the next block, got %v", e.pendingTxs)
+	}
+	e.generateBlocks(1)
+	if len(e.pendingTxs) != 0 {
+		t.Fatalf("expected no pending tx, got %d", len(e.pendingTxs))
+	}
+	if status := e.getTxProcessStatus("2"); status != "success" {
+		t.Fatalf("expected process status success, got %s", status)
+	}
+}
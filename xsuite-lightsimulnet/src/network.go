@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+)
+
+// NetworkParameters holds the values served by /network/config and enforced
+// when validating transactions.
+type NetworkParameters struct {
+	ChainID               string  `toml:"chain-id"`
+	MinTransactionVersion uint32  `toml:"min-tx-version"`
+	MaxTransactionVersion uint32  `toml:"max-tx-version"`
+	AddressHrp            string  `toml:"address-hrp"`
+	MinGasLimit           uint64  `toml:"min-gas-limit"`
+	GasPerDataByte        uint64  `toml:"gas-per-data-byte"`
+	MinGasPrice           uint64  `toml:"min-gas-price"`
+	GasPriceModifier      float64 `toml:"gas-price-modifier"`
+	MaxGasPerTransaction  uint64  `toml:"max-gas-per-tx"`
+	MaxGasPerBlock        uint64  `toml:"max-gas-per-block"`
+	Denomination          int     `toml:"denomination"`
+	NumShards             uint32  `toml:"num-of-shards"`
+	RoundDuration         uint64  `toml:"round-duration"`
+	RoundsPerEpoch        uint64  `toml:"rounds-per-epoch"`
+	StartTime             uint64  `toml:"start-time"`
+	EsdtIssueCost         string  `toml:"esdt-issue-cost"`
+}
+
+type networkProfile struct {
+	Network NetworkParameters `toml:"network"`
+}
+
+func DefaultNetworkParameters() NetworkParameters {
+	return NetworkParameters{
+		ChainID:               "S",
+		MinTransactionVersion: 1,
+		MaxTransactionVersion: 0,
+		AddressHrp:            core.DefaultAddressPrefix,
+		MinGasLimit:           50_000,
+		GasPerDataByte:        1_500,
+		MinGasPrice:           0,
+		GasPriceModifier:      0.01,
+		MaxGasPerTransaction:  600_000_000,
+		MaxGasPerBlock:        1_500_000_000,
+		Denomination:          18,
+		NumShards:             1,
+		RoundDuration:         6,
+		RoundsPerEpoch:        0,
+		StartTime:             0,
+		EsdtIssueCost:         "50000000000000000",
+	}
+}
+
+// LoadNetworkParameters reads the [network] table of a TOML profile. Keys
+// missing from the profile keep their default value.
+func LoadNetworkParameters(path string) (NetworkParameters, error) {
+	profile := networkProfile{Network: DefaultNetworkParameters()}
+	err := core.LoadTomlFile(&profile, path)
+	if err != nil {
+		return NetworkParameters{}, err
+	}
+	return profile.Network, nil
+}
+
+func (n NetworkParameters) validate() error {
+	if n.ChainID == "" {
+		return errors.New("chain ID must not be empty")
+	}
+	if n.AddressHrp == "" {
+		return errors.New("address HRP must not be empty")
+	}
+	if n.MaxTransactionVersion != 0 && n.MaxTransactionVersion < n.MinTransactionVersion {
+		return errors.New("max tx version must not be lower than min tx version")
+	}
+	if n.NumShards == 0 {
+		return errors.New("number of shards must be at least 1")
+	}
+	if n.MaxGasPerTransaction < n.MinGasLimit {
+		return errors.New("max gas per tx must not be lower than min gas limit")
+	}
+	if n.MaxGasPerBlock < n.MaxGasPerTransaction {
+		return errors.New("max gas per block must not be lower than max gas per tx")
+	}
+	esdtIssueCost, ok := new(big.Int).SetString(n.EsdtIssueCost, 10)
+	if !ok || esdtIssueCost.Sign() < 0 {
+		return errors.New("esdt issue cost must be a non-negative integer")
+	}
+	return nil
+}
+
+func (n NetworkParameters) esdtIssueCost() *big.Int {
+	esdtIssueCost, _ := new(big.Int).SetString(n.EsdtIssueCost, 10)
+	return esdtIssueCost
+}
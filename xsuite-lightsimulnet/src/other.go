@@ -47,6 +47,10 @@ func uint64ToString(value uint64) string {
 	return fmt.Sprintf("%d", value)
 }
 
+func bigUint64Bytes(value uint64) []byte {
+	return big.NewInt(0).SetUint64(value).Bytes()
+}
+
 func uint64ToBytesAddress(n uint64, isContract bool) []byte {
 	newAddress := make([]byte, addressByteLength)
 	shift := 0
@@ -62,8 +66,11 @@ func uint64ToBytesAddress(n uint64, isContract bool) []byte {
 	return newAddress
 }
 
+// addressHrp is set from the network profile before the server starts.
+var addressHrp = core.DefaultAddressPrefix
+
 func bech32Decode(input string) ([]byte, error) {
-	bpc, _ := pc.NewBech32PubkeyConverter(addressByteLength, core.DefaultAddressPrefix)
+	bpc, _ := pc.NewBech32PubkeyConverter(addressByteLength, addressHrp)
 	res, err := bpc.Decode(input)
 	if err != nil {
 		return nil, err
@@ -72,7 +79,7 @@ func bech32Decode(input string) ([]byte, error) {
 }
 
 func bech32Encode(input []byte) (string, error) {
-	bpc, _ := pc.NewBech32PubkeyConverter(addressByteLength, core.DefaultAddressPrefix)
+	bpc, _ := pc.NewBech32PubkeyConverter(addressByteLength, addressHrp)
 	res, err := bpc.Encode(input)
 	if err != nil {
 		return "", err
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+)
+
+var (
+	errInvalidRelayedTxData    = errors.New("invalid relayed transaction data")
+	errRecursiveRelayedTx      = errors.New("recursive relayed tx is not allowed")
+	errRelayedTxReceiver       = errors.New("relayed tx receiver is not the inner tx sender")
+	errRelayedTxValue          = errors.New("relayed tx value is not the inner tx value")
+	errRelayedTxGasPrice       = errors.New("relayed tx gas price is not the inner tx gas price")
+	errRelayedTxGasLimit       = errors.New("relayed tx gas limit is lower than the inner tx gas limit")
+	errRelayerNotInSenderShard = errors.New("relayer is not in the shard of the sender")
+)
+
+const (
+	relayedTxV1Prefix = core.RelayedTransaction + "@"
+	relayedTxV2Prefix = core.RelayedTransactionV2 + "@"
+)
+
+type relayedTx struct {
+	relayer []byte
+	innerTx RawTx
+	// The gas of the relaying itself, paid at the full gas price on top of
+	// the fee of the inner tx.
+	relayerGas uint64
+	// Relayed v1 and v2 txs are sent by the relayer, with its nonce, while
+	// relayed v3 txs are sent by the inner sender.
+	sentByRelayer bool
+	relayedValue  *big.Int
+	// The inner tx of a relayed v2 tx is signed without gas limit, and gets
+	// the gas left by the relayed tx once verified.
+	innerTxGasLimit uint64
+}
+
+func isRelayedTxData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(relayedTxV1Prefix)) || bytes.HasPrefix(data, []byte(relayedTxV2Prefix))
+}
+
+// Returns nil when the tx is not relayed.
+func (e *Executor) parseRelayedTx(rawTx RawTx, validation txValidation) (*relayedTx, error) {
+	data, err := getRawTxData(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	if rawTx.Relayer != "" {
+		if isRelayedTxData(data) {
+			return nil, errRecursiveRelayedTx
+		}
+		return e.parseRelayedTxV3(rawTx, data)
+	}
+	var relayedTx *relayedTx
+	if bytes.HasPrefix(data, []byte(relayedTxV1Prefix)) {
+		relayedTx, err = e.parseRelayedTxV1(rawTx, data)
+	} else if bytes.HasPrefix(data, []byte(relayedTxV2Prefix)) {
+		relayedTx, err = e.parseRelayedTxV2(rawTx, data)
+	} else {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	innerData, err := getRawTxData(relayedTx.innerTx)
+	if err != nil {
+		return nil, err
+	}
+	if relayedTx.innerTx.Relayer != "" || isRelayedTxData(innerData) {
+		return nil, errRecursiveRelayedTx
+	}
+	if validation.verifySignatures {
+		err = verifyTxSignatures(relayedTx.innerTx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if relayedTx.innerTxGasLimit > 0 {
+		relayedTx.innerTx.GasLimit = relayedTx.innerTxGasLimit
+	}
+	err = e.validateTxFields(relayedTx.innerTx, validation)
+	if err != nil {
+		return nil, err
+	}
+	return relayedTx, nil
+}
+
+// The relayer signs the tx of the sender, and pays an extra move balance gas.
+func (e *Executor) parseRelayedTxV3(rawTx RawTx, data []byte) (*relayedTx, error) {
+	relayer, err := bech32Decode(rawTx.Relayer)
+	if err != nil {
+		return nil, errInvalidRelayer
+	}
+	if rawTx.GasLimit < e.computeMoveBalanceGas(data)+e.network.MinGasLimit {
+		return nil, errInsufficientGasLimit
+	}
+	innerTx := rawTx
+	innerTx.GasLimit -= e.network.MinGasLimit
+	return &relayedTx{
+		relayer:      relayer,
+		innerTx:      innerTx,
+		relayerGas:   e.network.MinGasLimit,
+		relayedValue: big.NewInt(0),
+	}, nil
+}
+
+// The data is the hex of the JSON of the whole inner tx, which is sent by the
+// receiver of the relayed tx, with the value of the relayed tx.
+func (e *Executor) parseRelayedTxV1(rawTx RawTx, data []byte) (*relayedTx, error) {
+	innerTxBytes, err := hex.DecodeString(string(data[len(relayedTxV1Prefix):]))
+	if err != nil {
+		return nil, errInvalidRelayedTxData
+	}
+	tx := &transaction.Transaction{}
+	err = txSignMarshalizer.Unmarshal(tx, innerTxBytes)
+	if err != nil {
+		return nil, errInvalidRelayedTxData
+	}
+	relayer, err := bech32Decode(rawTx.Sender)
+	if err != nil {
+		return nil, err
+	}
+	if bech32Receiver, err := bech32Encode(tx.SndAddr); err != nil || bech32Receiver != rawTx.Receiver {
+		return nil, errRelayedTxReceiver
+	}
+	value, err := stringToBigint(rawTx.Value)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Value == nil {
+		tx.Value = big.NewInt(0)
+	}
+	if value.Cmp(tx.Value) != 0 {
+		return nil, errRelayedTxValue
+	}
+	if tx.GasPrice != rawTx.GasPrice {
+		return nil, errRelayedTxGasPrice
+	}
+	relayerGas := e.computeMoveBalanceGas(data)
+	if tx.GasLimit > rawTx.GasLimit-relayerGas {
+		return nil, errRelayedTxGasLimit
+	}
+	innerTx, err := transactionToRawTx(tx)
+	if err != nil {
+		return nil, errInvalidRelayedTxData
+	}
+	return &relayedTx{
+		relayer:       relayer,
+		innerTx:       innerTx,
+		relayerGas:    relayerGas,
+		sentByRelayer: true,
+		relayedValue:  value,
+	}, nil
+}
+
+// The data holds the receiver, nonce, data and signature of the inner tx,
+// which is sent by the receiver of the relayed tx, without value, with all the
+// gas left by the relayed tx.
+func (e *Executor) parseRelayedTxV2(rawTx RawTx, data []byte) (*relayedTx, error) {
+	dataParts := strings.Split(string(data), "@")
+	if len(dataParts) != 5 {
+		return nil, errInvalidRelayedTxData
+	}
+	receiver, err := hex.DecodeString(dataParts[1])
+	if err != nil {
+		return nil, errInvalidRelayedTxData
+	}
+	bech32Receiver, err := bech32Encode(receiver)
+	if err != nil {
+		return nil, errInvalidRelayedTxData
+	}
+	nonce, err := hexToUint64(dataParts[2])
+	if err != nil {
+		return nil, errInvalidRelayedTxData
+	}
+	innerData, err := hex.DecodeString(dataParts[3])
+	if err != nil {
+		return nil, errInvalidRelayedTxData
+	}
+	relayer, err := bech32Decode(rawTx.Sender)
+	if err != nil {
+		return nil, err
+	}
+	value, err := stringToBigint(rawTx.Value)
+	if err != nil {
+		return nil, err
+	}
+	if value.Sign() != 0 {
+		return nil, errRelayedTxValue
+	}
+	relayerGas := e.computeMoveBalanceGas(data)
+	base64InnerData := base64.StdEncoding.EncodeToString(innerData)
+	innerTx := RawTx{
+		Nonce:     nonce,
+		Value:     "0",
+		Receiver:  bech32Receiver,
+		Sender:    rawTx.Receiver,
+		GasPrice:  rawTx.GasPrice,
+		GasLimit:  0,
+		Data:      &base64InnerData,
+		Signature: dataParts[4],
+		ChainID:   rawTx.ChainID,
+		Version:   rawTx.Version,
+	}
+	return &relayedTx{
+		relayer:         relayer,
+		innerTx:         innerTx,
+		relayerGas:      relayerGas,
+		sentByRelayer:   true,
+		relayedValue:    value,
+		innerTxGasLimit: rawTx.GasLimit - relayerGas,
+	}, nil
+}
+
+// The value of a relayed v1 tx goes from the relayer to the inner sender
+// before the inner tx is executed. Unlike on the node, the inner tx is executed
+// right away, even when the relayer is in another shard.
+func (e *Executor) executeRelayedTx(txHash string, rawTx RawTx, relayedTx *relayedTx) error {
+	world := e.scenexec.World
+	relayerAccount := world.AcctMap.GetAccount(relayedTx.relayer)
+	if relayerAccount == nil {
+		return errAccountNotFound
+	}
+	sender, err := bech32Decode(relayedTx.innerTx.Sender)
+	if err != nil {
+		return err
+	}
+	senderAccount := world.AcctMap.GetAccount(sender)
+	if senderAccount == nil {
+		return errAccountNotFound
+	}
+	if !relayedTx.sentByRelayer && e.getShardOf(relayedTx.relayer) != e.getShardOf(sender) {
+		return errRelayerNotInSenderShard
+	}
+	if relayedTx.sentByRelayer && relayerAccount.Nonce != rawTx.Nonce {
+		return errors.New("invalid nonce")
+	}
+	if !e.hasFundsForTx(relayerAccount, rawTx, relayedTx.relayedValue) {
+		if !relayedTx.sentByRelayer {
+			return errInsufficientFunds
+		}
+		return e.setInsufficientFundsTx(txHash, rawTx, relayerAccount)
+	}
+	relayerAccount.Balance = new(big.Int).Sub(relayerAccount.Balance, relayedTx.relayedValue)
+	senderAccount.Balance = new(big.Int).Add(senderAccount.Balance, relayedTx.relayedValue)
+	if relayedTx.sentByRelayer {
+		relayerAccount.Nonce += 1
+	}
+	err = e.executeUserTx(txHash, relayedTx.innerTx, relayedTx)
+	if err != nil {
+		relayerAccount.Balance = new(big.Int).Add(relayerAccount.Balance, relayedTx.relayedValue)
+		senderAccount.Balance = new(big.Int).Sub(senderAccount.Balance, relayedTx.relayedValue)
+		if relayedTx.sentByRelayer {
+			relayerAccount.Nonce -= 1
+		}
+		return err
+	}
+	return nil
+}
+
+// The scenario executor takes the execution gas from the sender, so the relayer
+// advances it, and gets it back when the fees are settled. Advancing it from
+// the sender to the relayer reverts the advance.
+func (e *Executor) advanceRelayedTxGas(from []byte, to []byte, executionGasLimit uint64, gasPrice uint64) {
+	world := e.scenexec.World
+	gas := new(big.Int).Mul(
+		new(big.Int).SetUint64(executionGasLimit),
+		new(big.Int).SetUint64(gasPrice),
+	)
+	fromAccount := world.AcctMap.GetAccount(from)
+	fromAccount.Balance = new(big.Int).Sub(fromAccount.Balance, gas)
+	toAccount := world.AcctMap.GetAccount(to)
+	toAccount.Balance = new(big.Int).Add(toAccount.Balance, gas)
+}
+
+func (e *Executor) addRelayerGasToFees(fees txFees, relayerGas uint64, gasPrice uint64) txFees {
+	relayerFee := new(big.Int).Mul(
+		new(big.Int).SetUint64(relayerGas),
+		new(big.Int).SetUint64(gasPrice),
+	)
+	fees.gasUsed += relayerGas
+	fees.initiallyPaidFee = new(big.Int).Add(fees.initiallyPaidFee, relayerFee)
+	fees.fee = new(big.Int).Add(fees.fee, relayerFee)
+	return fees
+}
+
+func transactionToRawTx(tx *transaction.Transaction) (RawTx, error) {
+	receiver, err := bech32Encode(tx.RcvAddr)
+	if err != nil {
+		return RawTx{}, err
+	}
+	sender, err := bech32Encode(tx.SndAddr)
+	if err != nil {
+		return RawTx{}, err
+	}
+	var guardian string
+	if len(tx.GuardianAddr) > 0 {
+		guardian, err = bech32Encode(tx.GuardianAddr)
+		if err != nil {
+			return RawTx{}, err
+		}
+	}
+	var relayer string
+	if len(tx.RelayerAddr) > 0 {
+		relayer, err = bech32Encode(tx.RelayerAddr)
+		if err != nil {
+			return RawTx{}, err
+		}
+	}
+	value := "0"
+	if tx.Value != nil {
+		value = tx.Value.String()
+	}
+	data := base64.StdEncoding.EncodeToString(tx.Data)
+	return RawTx{
+		Nonce:             tx.Nonce,
+		Value:             value,
+		Receiver:          receiver,
+		Sender:            sender,
+		GasPrice:          tx.GasPrice,
+		GasLimit:          tx.GasLimit,
+		Data:              &data,
+		Signature:         hex.EncodeToString(tx.Signature),
+		ChainID:           string(tx.ChainID),
+		Version:           uint64(tx.Version),
+		Options:           tx.Options,
+		Guardian:          guardian,
+		GuardianSignature: hex.EncodeToString(tx.GuardianSignature),
+		Relayer:           relayer,
+		RelayerSignature:  hex.EncodeToString(tx.RelayerSignature),
+	}, nil
+}
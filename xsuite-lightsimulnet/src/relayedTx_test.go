@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+func signTestTx(t *testing.T, privateKey ed25519.PrivateKey, rawTx RawTx) string {
+	tx, err := rawTxToTransaction(rawTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, err := tx.GetDataForSigning(&bech32AddressEncoder{}, txSignMarshalizer, txSignHasher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(ed25519.Sign(privateKey, message))
+}
+
+// The inner tx of a relayed v2 tx is signed with a gas limit of 0, and
+// executed with the gas left by the relayed tx.
+func TestSignedRelayedTxV2(t *testing.T) {
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots:     100,
+		VerifySignatures: true,
+		Network:          DefaultNetworkParameters(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	senderPublicKey, senderPrivateKey, _ := ed25519.GenerateKey(nil)
+	relayerPublicKey, relayerPrivateKey, _ := ed25519.GenerateKey(nil)
+	sender := setTestAccount(t, e, senderPublicKey, nil)
+	relayer := setTestAccount(t, e, relayerPublicKey, nil)
+	balance := "1000000000000000000"
+	err = e.updateAccount(RawAccount{Address: relayer, Balance: &balance})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiverAddress := uint64ToBytesAddress(1, false)
+	receiver := setTestAccount(t, e, receiverAddress, nil)
+
+	getRelayedTx := func(innerGasLimit uint64) RawTx {
+		innerData := base64.StdEncoding.EncodeToString([]byte("hello"))
+		innerSignature := signTestTx(t, senderPrivateKey, RawTx{
+			Value:    "0",
+			Receiver: receiver,
+			Sender:   sender,
+			GasPrice: 1_000_000_000,
+			GasLimit: innerGasLimit,
+			Data:     &innerData,
+			ChainID:  "S",
+			Version:  1,
+		})
+		data := base64.StdEncoding.EncodeToString([]byte("relayedTxV2@" + hex.EncodeToString(receiverAddress) +
+			"@@" + hex.EncodeToString([]byte("hello")) + "@" + innerSignature))
+		rawTx := RawTx{
+			Value:    "0",
+			Receiver: sender,
+			Sender:   relayer,
+			GasPrice: 1_000_000_000,
+			GasLimit: 1_000_000,
+			Data:     &data,
+			ChainID:  "S",
+			Version:  1,
+		}
+		rawTx.Signature = signTestTx(t, relayerPrivateKey, rawTx)
+		return rawTx
+	}
+
+	err = e.executeTx("1", getRelayedTx(500_000))
+	if err != errInvalidSignature {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+	err = e.executeTx("2", getRelayedTx(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := e.getTxProcessStatus("2"); status != "success" {
+		t.Fatalf("expected process status success, got %s", status)
+	}
+	if nonce := e.scenexec.World.AcctMap.GetAccount(senderPublicKey).Nonce; nonce != 1 {
+		t.Fatalf("expected sender nonce 1, got %d", nonce)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+)
+
+var errInvalidShard = errors.New("invalid shard")
+
+func (e *Executor) isMultiShard() bool {
+	return e.network.NumShards > 1
+}
+
+// The shard is computed from the last byte of the address like the protocol
+// does, the system smart contracts living on the metachain.
+func computeShardID(address []byte, numShards uint32) uint32 {
+	if len(address) == 0 {
+		return 0
+	}
+	lastByte := address[len(address)-1:]
+	if core.IsSmartContractOnMetachain(lastByte, address) {
+		return core.MetachainShardId
+	}
+	if numShards <= 1 {
+		return 0
+	}
+	n := bits.Len32(numShards - 1)
+	maskHigh := uint32(1)<<n - 1
+	maskLow := uint32(1)<<(n-1) - 1
+	shard := uint32(lastByte[0]) & maskHigh
+	if shard >= numShards {
+		shard = uint32(lastByte[0]) & maskLow
+	}
+	return shard
+}
+
+// With a single shard, the ESDT system smart contract still lives on the
+// metachain, where its calls are emulated.
+func (e *Executor) getShardOf(address []byte) uint32 {
+	if !e.isMultiShard() && !isEsdtSystemSc(address) {
+		return 0
+	}
+	return computeShardID(address, e.network.NumShards)
+}
+
+// The world only lets the VM and the built-in functions touch the accounts
+// of its own shard, so every account is placed in its shard before a step.
+// Accounts created during a step land in shard 0 until the next one.
+func (e *Executor) assignAccountShards() {
+	if !e.isMultiShard() {
+		return
+	}
+	for _, account := range e.scenexec.World.AcctMap {
+		account.ShardID = e.getShardOf(account.Address)
+	}
+}
+
+func (e *Executor) setSelfShard(shard uint32) {
+	e.scenexec.World.SelfShardID = shard
+	e.assignAccountShards()
+}
+
+// The built-in functions look up the shard of the receiver in the world, so
+// it must exist before they run.
+func (e *Executor) ensureAccountInShard(address []byte) {
+	world := e.scenexec.World
+	if world.AcctMap.GetAccount(address) != nil {
+		return
+	}
+	account := world.AcctMap.CreateAccount(address, world)
+	account.ShardID = e.getShardOf(address)
+}
+
+func (e *Executor) parseShard(shardStr string) (uint32, error) {
+	shard, err := strconv.ParseUint(shardStr, 10, 32)
+	if err != nil {
+		return 0, errInvalidShard
+	}
+	if uint32(shard) != core.MetachainShardId && uint32(shard) >= e.network.NumShards {
+		return 0, errInvalidShard
+	}
+	return uint32(shard), nil
+}
+
+// Shards notarize each other through the metachain, which itself checks every
+// shard. All of them produce a block at each round here.
+func (e *Executor) getCrossCheckBlockHeight(shard uint32) string {
+	if !e.isMultiShard() {
+		return "-1"
+	}
+	nonce := e.scenexec.World.CurrentNonce()
+	if shard != core.MetachainShardId {
+		return fmt.Sprintf("meta %d", nonce)
+	}
+	heights := []string{}
+	for i := uint32(0); i < e.network.NumShards; i++ {
+		heights = append(heights, fmt.Sprintf("%d: %d", i, nonce))
+	}
+	return strings.Join(heights, ", ")
+}
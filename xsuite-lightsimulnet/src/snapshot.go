@@ -0,0 +1,122 @@
+package main
+
+import (
+	"math/big"
+
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+)
+
+type worldSnapshot struct {
+	acctMap              worldmock.AccountMap
+	currentBlockInfo     *worldmock.BlockInfo
+	previousBlockInfo    *worldmock.BlockInfo
+	newAddressMocks      []*worldmock.NewAddressMock
+	hashesOfTxsToKeep    []string
+	txResps              map[string]interface{}
+	txProcessStatusResps map[string]interface{}
+	txCounter            uint64
+	scCounter            uint64
+	pendingTxs           []pendingTx
+	totalFees            *big.Int
+	totalDevRewards      *big.Int
+	crossShardScrs       []crossShardScr
+}
+
+func (e *Executor) takeSnapshot() *worldSnapshot {
+	s := &worldSnapshot{
+		acctMap:              e.scenexec.World.AcctMap,
+		currentBlockInfo:     e.scenexec.World.CurrentBlockInfo,
+		previousBlockInfo:    e.scenexec.World.PreviousBlockInfo,
+		newAddressMocks:      e.scenexec.World.NewAddressMocks,
+		hashesOfTxsToKeep:    e.hashesOfTxsToKeep,
+		txResps:              e.txResps,
+		txProcessStatusResps: e.txProcessStatusResps,
+		txCounter:            e.txCounter,
+		scCounter:            e.scCounter,
+		pendingTxs:           e.pendingTxs,
+		totalFees:            e.totalFees,
+		totalDevRewards:      e.totalDevRewards,
+		crossShardScrs:       e.crossShardScrs,
+	}
+	return s.clone()
+}
+
+func (e *Executor) restoreSnapshot(snapshot *worldSnapshot) {
+	s := snapshot.clone()
+	e.scenexec.World.AcctMap = s.acctMap
+	e.scenexec.World.CurrentBlockInfo = s.currentBlockInfo
+	e.scenexec.World.PreviousBlockInfo = s.previousBlockInfo
+	e.scenexec.World.NewAddressMocks = s.newAddressMocks
+	e.hashesOfTxsToKeep = s.hashesOfTxsToKeep
+	e.txResps = s.txResps
+	e.txProcessStatusResps = s.txProcessStatusResps
+	e.txCounter = s.txCounter
+	e.scCounter = s.scCounter
+	e.pendingTxs = s.pendingTxs
+	e.totalFees = s.totalFees
+	e.totalDevRewards = s.totalDevRewards
+	e.crossShardScrs = s.crossShardScrs
+}
+
+func (s *worldSnapshot) clone() *worldSnapshot {
+	newAddressMocks := make([]*worldmock.NewAddressMock, 0, len(s.newAddressMocks))
+	for _, mock := range s.newAddressMocks {
+		newAddressMocks = append(newAddressMocks, &worldmock.NewAddressMock{
+			CreatorAddress: append([]byte{}, mock.CreatorAddress...),
+			CreatorNonce:   mock.CreatorNonce,
+			NewAddress:     append([]byte{}, mock.NewAddress...),
+		})
+	}
+	txResps := cloneTxResp(s.txResps).(map[string]interface{})
+	txProcessStatusResps := cloneTxResp(s.txProcessStatusResps).(map[string]interface{})
+	return &worldSnapshot{
+		acctMap:              s.acctMap.Clone(),
+		currentBlockInfo:     cloneBlockInfo(s.currentBlockInfo),
+		previousBlockInfo:    cloneBlockInfo(s.previousBlockInfo),
+		newAddressMocks:      newAddressMocks,
+		hashesOfTxsToKeep:    append([]string{}, s.hashesOfTxsToKeep...),
+		txResps:              txResps,
+		txProcessStatusResps: txProcessStatusResps,
+		txCounter:            s.txCounter,
+		scCounter:            s.scCounter,
+		pendingTxs:           append([]pendingTx{}, s.pendingTxs...),
+		totalFees:            new(big.Int).Set(s.totalFees),
+		totalDevRewards:      new(big.Int).Set(s.totalDevRewards),
+		crossShardScrs:       append([]crossShardScr{}, s.crossShardScrs...),
+	}
+}
+
+func cloneBlockInfo(blockInfo *worldmock.BlockInfo) *worldmock.BlockInfo {
+	if blockInfo == nil {
+		return nil
+	}
+	clone := *blockInfo
+	if blockInfo.RandomSeed != nil {
+		randomSeed := *blockInfo.RandomSeed
+		clone.RandomSeed = &randomSeed
+	}
+	return &clone
+}
+
+// Tx responses are trees of maps and slices, the leaves being immutable
+// values, so copying the maps and slices is enough to isolate them.
+func cloneTxResp(resp interface{}) interface{} {
+	switch resp := resp.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(resp))
+		for k, v := range resp {
+			clone[k] = cloneTxResp(v)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(resp))
+		for i, v := range resp {
+			clone[i] = cloneTxResp(v)
+		}
+		return clone
+	case []string:
+		return append([]string{}, resp...)
+	default:
+		return resp
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestSnapshotIsolatesTxResps(t *testing.T) {
+	e := newTestExecutor(t)
+	e.txResps["hash"] = map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"status": "pending",
+			"logs": map[string]interface{}{
+				"events": []interface{}{
+					map[string]interface{}{"topics": []string{"AQ=="}},
+				},
+			},
+		},
+	}
+	snapshot := e.takeSnapshot()
+	tx := e.txResps["hash"].(map[string]interface{})["transaction"].(map[string]interface{})
+	tx["status"] = "success"
+	event := tx["logs"].(map[string]interface{})["events"].([]interface{})[0].(map[string]interface{})
+	event["topics"].([]string)[0] = "Ag=="
+
+	e.restoreSnapshot(snapshot)
+	tx = e.txResps["hash"].(map[string]interface{})["transaction"].(map[string]interface{})
+	if tx["status"] != "pending" {
+		t.Fatalf("expected status pending, got %v", tx["status"])
+	}
+	event = tx["logs"].(map[string]interface{})["events"].([]interface{})[0].(map[string]interface{})
+	if topic := event["topics"].([]string)[0]; topic != "AQ==" {
+		t.Fatalf("expected topic AQ==, got %s", topic)
+	}
+}
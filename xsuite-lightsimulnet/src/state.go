@@ -0,0 +1,384 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/data/vm"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+)
+
+var errStateFilePathNotAllowed = errors.New("state file path is neither the state file nor in the state directory")
+
+func (e *Executor) saveStateFile(path string) error {
+	stateData, err := e.getStateData()
+	if err != nil {
+		return err
+	}
+	stateJson, err := json.MarshalIndent(stateData, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, stateJson, 0600)
+}
+
+func (e *Executor) loadStateFile(path string) error {
+	stateJson, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var rawState RawState
+	err = json.Unmarshal(stateJson, &rawState)
+	if err != nil {
+		return err
+	}
+	return e.loadState(rawState)
+}
+
+func (e *Executor) getStateData() (interface{}, error) {
+	accountsData := []map[string]interface{}{}
+	for _, worldAccount := range e.scenexec.World.AcctMap {
+		accountData, err := e.getRawAccountData(worldAccount)
+		if err != nil {
+			return nil, err
+		}
+		accountsData = append(accountsData, accountData)
+	}
+	sort.Slice(accountsData, func(i, j int) bool {
+		return accountsData[i]["address"].(string) < accountsData[j]["address"].(string)
+	})
+	newAddressMocksData := []interface{}{}
+	for _, mock := range e.scenexec.World.NewAddressMocks {
+		bechCreatorAddress, err := bech32Encode(mock.CreatorAddress)
+		if err != nil {
+			return nil, err
+		}
+		bechNewAddress, err := bech32Encode(mock.NewAddress)
+		if err != nil {
+			return nil, err
+		}
+		newAddressMocksData = append(newAddressMocksData, map[string]interface{}{
+			"creatorAddress": bechCreatorAddress,
+			"creatorNonce":   mock.CreatorNonce,
+			"newAddress":     bechNewAddress,
+		})
+	}
+	txsData := []interface{}{}
+	for _, txHash := range e.hashesOfTxsToKeep {
+		txsData = append(txsData, map[string]interface{}{
+			"hash":          txHash,
+			"resp":          e.txResps[txHash],
+			"processStatus": e.txProcessStatusResps[txHash],
+		})
+	}
+	pendingTxsData := []interface{}{}
+	for _, pending := range e.pendingTxs {
+		pendingTxsData = append(pendingTxsData, map[string]interface{}{
+			"hash": pending.hash,
+			"tx":   pending.rawTx,
+		})
+	}
+	crossShardScrsData := []interface{}{}
+	for _, scr := range e.crossShardScrs {
+		scrData, err := getRawCrossShardScrData(scr)
+		if err != nil {
+			return nil, err
+		}
+		crossShardScrsData = append(crossShardScrsData, scrData)
+	}
+	data := map[string]interface{}{
+		"accounts":          accountsData,
+		"currentBlockInfo":  getBlockData(e.scenexec.World.CurrentBlockInfo),
+		"previousBlockInfo": getBlockData(e.scenexec.World.PreviousBlockInfo),
+		"newAddressMocks":   newAddressMocksData,
+		"txs":               txsData,
+		"txCounter":         e.txCounter,
+		"scCounter":         e.scCounter,
+		"pendingTxs":        pendingTxsData,
+		"totalFees":         e.totalFees.String(),
+		"totalDevRewards":   e.totalDevRewards.String(),
+		"crossShardScrs":    crossShardScrsData,
+	}
+	return data, nil
+}
+
+func (e *Executor) loadState(rawState RawState) error {
+	currentBlockInfo, err := blockToBlockInfo(rawState.CurrentBlockInfo)
+	if err != nil {
+		return err
+	}
+	previousBlockInfo, err := blockToBlockInfo(rawState.PreviousBlockInfo)
+	if err != nil {
+		return err
+	}
+	totalFees, err := stringToBigint(rawState.TotalFees)
+	if err != nil {
+		return err
+	}
+	totalDevRewards, err := stringToBigint(rawState.TotalDevRewards)
+	if err != nil {
+		return err
+	}
+	crossShardScrs := []crossShardScr{}
+	for _, rawScr := range rawState.CrossShardScrs {
+		scr, err := rawToCrossShardScr(rawScr)
+		if err != nil {
+			return err
+		}
+		crossShardScrs = append(crossShardScrs, scr)
+	}
+	previousAcctMap := e.scenexec.World.AcctMap
+	e.scenexec.World.AcctMap = worldmock.NewAccountMap()
+	for _, rawAccount := range rawState.Accounts {
+		err := e.setAccount(rawAccount)
+		if err != nil {
+			e.scenexec.World.AcctMap = previousAcctMap
+			return err
+		}
+	}
+	newAddressMocks := []*worldmock.NewAddressMock{}
+	for _, rawMock := range rawState.NewAddressMocks {
+		creatorAddress, err := bech32Decode(rawMock.CreatorAddress)
+		if err != nil {
+			e.scenexec.World.AcctMap = previousAcctMap
+			return err
+		}
+		newAddress, err := bech32Decode(rawMock.NewAddress)
+		if err != nil {
+			e.scenexec.World.AcctMap = previousAcctMap
+			return err
+		}
+		newAddressMocks = append(newAddressMocks, &worldmock.NewAddressMock{
+			CreatorAddress: creatorAddress,
+			CreatorNonce:   rawMock.CreatorNonce,
+			NewAddress:     newAddress,
+		})
+	}
+	e.scenexec.World.NewAddressMocks = newAddressMocks
+	e.scenexec.World.CurrentBlockInfo = currentBlockInfo
+	e.scenexec.World.PreviousBlockInfo = previousBlockInfo
+	e.hashesOfTxsToKeep = []string{}
+	e.txResps = map[string]interface{}{}
+	e.txProcessStatusResps = map[string]interface{}{}
+	for _, rawTx := range rawState.Txs {
+		e.hashesOfTxsToKeep = append(e.hashesOfTxsToKeep, rawTx.Hash)
+		e.txResps[rawTx.Hash] = rawTx.Resp
+		e.txProcessStatusResps[rawTx.Hash] = rawTx.ProcessStatus
+	}
+	e.txCounter = rawState.TxCounter
+	e.scCounter = rawState.ScCounter
+	e.pendingTxs = []pendingTx{}
+	for _, rawPendingTx := range rawState.PendingTxs {
+		e.pendingTxs = append(e.pendingTxs, pendingTx{hash: rawPendingTx.Hash, rawTx: rawPendingTx.Tx})
+	}
+	e.totalFees = totalFees
+	e.totalDevRewards = totalDevRewards
+	e.crossShardScrs = crossShardScrs
+	return nil
+}
+
+func (e *Executor) getRawAccountData(worldAccount *worldmock.Account) (map[string]interface{}, error) {
+	bechAddress, err := bech32Encode(worldAccount.Address)
+	if err != nil {
+		return nil, err
+	}
+	var bechOwnerAddress string
+	if len(worldAccount.OwnerAddress) > 0 {
+		bechOwnerAddress, err = bech32Encode(worldAccount.OwnerAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data := map[string]interface{}{
+		"address":      bechAddress,
+		"nonce":        worldAccount.Nonce,
+		"balance":      worldAccount.Balance.String(),
+		"kvs":          e.getAccountKvsData(worldAccount),
+		"code":         hex.EncodeToString(worldAccount.Code),
+		"codeMetadata": hex.EncodeToString(worldAccount.CodeMetadata),
+		"owner":        bechOwnerAddress,
+		"developerReward": getDeveloperRewardData(worldAccount),
+	}
+	return data, nil
+}
+
+func getBlockData(blockInfo *worldmock.BlockInfo) interface{} {
+	if blockInfo == nil {
+		return nil
+	}
+	data := map[string]interface{}{
+		"timestamp": blockInfo.BlockTimestamp,
+		"nonce":     blockInfo.BlockNonce,
+		"round":     blockInfo.BlockRound,
+		"epoch":     blockInfo.BlockEpoch,
+	}
+	if blockInfo.RandomSeed != nil {
+		data["randomSeed"] = hex.EncodeToString(blockInfo.RandomSeed[:])
+	}
+	return data
+}
+
+func blockToBlockInfo(block *Block) (*worldmock.BlockInfo, error) {
+	if block == nil {
+		return nil, nil
+	}
+	var randomSeed *[48]byte
+	if block.RandomSeed != nil {
+		randomSeedBytes, err := hex.DecodeString(*block.RandomSeed)
+		if err != nil {
+			return nil, err
+		}
+		if len(randomSeedBytes) != 48 {
+			return nil, errors.New("random seed must be 48 bytes long")
+		}
+		randomSeed = &[48]byte{}
+		copy(randomSeed[:], randomSeedBytes)
+	}
+	blockInfo := &worldmock.BlockInfo{
+		BlockTimestamp: block.Timestamp,
+		BlockNonce:     block.Nonce,
+		BlockRound:     block.Round,
+		BlockEpoch:     block.Epoch,
+		RandomSeed:     randomSeed,
+	}
+	return blockInfo, nil
+}
+
+type RawState struct {
+	Accounts          []RawAccount
+	CurrentBlockInfo  *Block
+	PreviousBlockInfo *Block
+	NewAddressMocks   []RawNewAddressMock
+	Txs               []RawStateTx
+	TxCounter         uint64
+	ScCounter         uint64
+	PendingTxs        []RawStatePendingTx
+	TotalFees         string
+	TotalDevRewards   string
+	CrossShardScrs    []RawStateCrossShardScr
+}
+
+type RawNewAddressMock struct {
+	CreatorAddress string
+	CreatorNonce   uint64
+	NewAddress     string
+}
+
+type RawStateTx struct {
+	Hash          string
+	Resp          interface{}
+	ProcessStatus interface{}
+}
+
+type RawStatePendingTx struct {
+	Hash string
+	Tx   RawTx
+}
+
+type RawStateCrossShardScr struct {
+	Hash                 string
+	TxHash               string
+	PrevTxHash           string
+	Sender               string
+	Receiver             string
+	OriginalSender       string
+	Relayer              string
+	Value                string
+	Data                 string
+	GasLimit             uint64
+	GasLocked            uint64
+	GasPrice             uint64
+	CallType             vm.CallType
+	AsyncData            string
+	ReturnCallAfterError bool
+}
+
+func getRawCrossShardScrData(scr crossShardScr) (map[string]interface{}, error) {
+	bechSender, err := bech32Encode(scr.sender)
+	if err != nil {
+		return nil, err
+	}
+	bechReceiver, err := bech32Encode(scr.receiver)
+	if err != nil {
+		return nil, err
+	}
+	bechOriginalSender, err := bech32Encode(scr.originalSender)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"hash":                 scr.hash,
+		"txHash":               scr.txHash,
+		"prevTxHash":           scr.prevTxHash,
+		"sender":               bechSender,
+		"receiver":             bechReceiver,
+		"originalSender":       bechOriginalSender,
+		"value":                scr.value.String(),
+		"data":                 string(scr.data),
+		"gasLimit":             scr.gasLimit,
+		"gasLocked":            scr.gasLocked,
+		"gasPrice":             scr.gasPrice,
+		"callType":             scr.callType,
+		"asyncData":            hex.EncodeToString(scr.asyncData),
+		"returnCallAfterError": scr.returnCallAfterError,
+	}
+	if len(scr.relayer) > 0 {
+		bechRelayer, err := bech32Encode(scr.relayer)
+		if err != nil {
+			return nil, err
+		}
+		data["relayer"] = bechRelayer
+	}
+	return data, nil
+}
+
+func rawToCrossShardScr(rawScr RawStateCrossShardScr) (crossShardScr, error) {
+	sender, err := bech32Decode(rawScr.Sender)
+	if err != nil {
+		return crossShardScr{}, err
+	}
+	receiver, err := bech32Decode(rawScr.Receiver)
+	if err != nil {
+		return crossShardScr{}, err
+	}
+	originalSender, err := bech32Decode(rawScr.OriginalSender)
+	if err != nil {
+		return crossShardScr{}, err
+	}
+	var relayer []byte
+	if rawScr.Relayer != "" {
+		relayer, err = bech32Decode(rawScr.Relayer)
+		if err != nil {
+			return crossShardScr{}, err
+		}
+	}
+	value, err := stringToBigint(rawScr.Value)
+	if err != nil {
+		return crossShardScr{}, err
+	}
+	asyncData, err := hex.DecodeString(rawScr.AsyncData)
+	if err != nil {
+		return crossShardScr{}, err
+	}
+	scr := crossShardScr{
+		hash:                 rawScr.Hash,
+		txHash:               rawScr.TxHash,
+		prevTxHash:           rawScr.PrevTxHash,
+		sender:               sender,
+		receiver:             receiver,
+		originalSender:       originalSender,
+		relayer:              relayer,
+		value:                value,
+		data:                 []byte(rawScr.Data),
+		gasLimit:             rawScr.GasLimit,
+		gasLocked:            rawScr.GasLocked,
+		gasPrice:             rawScr.GasPrice,
+		callType:             rawScr.CallType,
+		asyncData:            asyncData,
+		returnCallAfterError: rawScr.ReturnCallAfterError,
+	}
+	return scr, nil
+}
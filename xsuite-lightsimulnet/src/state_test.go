@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func saveTestState(e *Executor, path string) error {
+	reqBody, _ := json.Marshal(map[string]interface{}{"path": path})
+	_, err := e.HandleAdminSaveState(httptest.NewRequest("POST", "/admin/save-state", bytes.NewReader(reqBody)))
+	return err
+}
+
+func TestStateFilePathsAreRestricted(t *testing.T) {
+	stateDir := t.TempDir()
+	otherDir := t.TempDir()
+	e, err := NewExecutor(ExecutorConfig{
+		MaxSnapshots: 100,
+		StateFile:    filepath.Join(otherDir, "state.json"),
+		StateDir:     stateDir,
+		Network:      DefaultNetworkParameters(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{
+		"state.json",
+		filepath.Join(stateDir, "state.json"),
+		filepath.Join(otherDir, "state.json"),
+	} {
+		err = saveTestState(e, path)
+		if err != nil {
+			t.Fatalf("expected %s to be allowed, got %v", path, err)
+		}
+	}
+	for _, path := range []string{
+		"../state.json",
+		filepath.Join(stateDir, "..", "state.json"),
+		filepath.Join(otherDir, "other.json"),
+	} {
+		err = saveTestState(e, path)
+		if err != errStateFilePathNotAllowed {
+			t.Fatalf("expected %s to be rejected, got %v", path, err)
+		}
+	}
+}
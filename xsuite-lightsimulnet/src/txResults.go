@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/smartContractResult"
+	"github.com/multiversx/mx-chain-core-go/data/vm"
+	model "github.com/multiversx/mx-chain-scenario-go/scenario/model"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+)
+
+func getLogEventData(logEntry *vmcommon.LogEntry) (interface{}, error) {
+	var bechAddress string
+	var err error
+	if len(logEntry.Address) > 0 {
+		bechAddress, err = bech32Encode(logEntry.Address)
+		if err != nil {
+			return nil, err
+		}
+	}
+	topics := []string{}
+	for _, topic := range logEntry.Topics {
+		topics = append(topics, base64.StdEncoding.EncodeToString(topic))
+	}
+	var data interface{}
+	if firstDataItem := logEntry.GetFirstDataItem(); firstDataItem != nil {
+		data = base64.StdEncoding.EncodeToString(firstDataItem)
+	}
+	var additionalData interface{}
+	if len(logEntry.Data) > 0 {
+		b64AdditionalData := []string{}
+		for _, dataItem := range logEntry.Data {
+			b64AdditionalData = append(b64AdditionalData, base64.StdEncoding.EncodeToString(dataItem))
+		}
+		additionalData = b64AdditionalData
+	}
+	event := map[string]interface{}{
+		"address":        bechAddress,
+		"identifier":     string(logEntry.Identifier),
+		"topics":         topics,
+		"data":           data,
+		"additionalData": additionalData,
+	}
+	return event, nil
+}
+
+// The scenario executor runs the ESDT built-in function of a SC call on its
+// own and drops its output, so its transfer log is rebuilt here the way the
+// built-in function writes it.
+func getEsdtTransferLogEntry(tx *model.Transaction, function string) *vmcommon.LogEntry {
+	topics := [][]byte{}
+	arguments := [][]byte{}
+	if function == core.BuiltInFunctionMultiESDTNFTTransfer {
+		arguments = append(arguments, tx.To.Value, bigUint64Bytes(uint64(len(tx.ESDTValue))))
+	}
+	for _, esdtValue := range tx.ESDTValue {
+		nonce := bigUint64Bytes(esdtValue.Nonce.Value)
+		topics = append(topics, esdtValue.TokenIdentifier.Value, nonce, esdtValue.Value.Value.Bytes())
+		if function == core.BuiltInFunctionESDTTransfer {
+			arguments = append(arguments, esdtValue.TokenIdentifier.Value, esdtValue.Value.Value.Bytes())
+		} else {
+			arguments = append(arguments, esdtValue.TokenIdentifier.Value, nonce, esdtValue.Value.Value.Bytes())
+		}
+	}
+	if function == core.BuiltInFunctionESDTNFTTransfer {
+		arguments = append(arguments, tx.To.Value)
+	}
+	topics = append(topics, tx.To.Value)
+	if tx.Function != "" {
+		arguments = append(arguments, []byte(tx.Function))
+		arguments = append(arguments, model.JSONBytesFromTreeValues(tx.Arguments)...)
+	}
+	return &vmcommon.LogEntry{
+		Identifier: []byte(function),
+		Address:    tx.From.Value,
+		Topics:     topics,
+		Data:       vmcommon.FormatLogDataForCall("", function, arguments),
+	}
+}
+
+type indexedTransfer struct {
+	receiver []byte
+	transfer vmcommon.OutputTransfer
+}
+
+// The transfers are ordered like the VM created them, which gives the index
+// of their smart contract result.
+func getSortedOutputTransfers(vmOutput *vmcommon.VMOutput) []indexedTransfer {
+	transfers := []indexedTransfer{}
+	for _, outputAccount := range vmOutput.OutputAccounts {
+		for _, transfer := range outputAccount.OutputTransfers {
+			transfers = append(transfers, indexedTransfer{
+				receiver: outputAccount.Address,
+				transfer: transfer,
+			})
+		}
+	}
+	sort.SliceStable(transfers, func(i, j int) bool {
+		if transfers[i].transfer.Index != transfers[j].transfer.Index {
+			return transfers[i].transfer.Index < transfers[j].transfer.Index
+		}
+		return bytes.Compare(transfers[i].receiver, transfers[j].receiver) < 0
+	})
+	return transfers
+}
+
+func (e *Executor) getOutputTransfersScrsData(
+	prevTxHash string,
+	txHash string,
+	originalSender []byte,
+	defaultSender []byte,
+	gasPrice uint64,
+	vmOutput *vmcommon.VMOutput,
+) ([]interface{}, error) {
+	scrsData := []interface{}{}
+	for i, t := range getSortedOutputTransfers(vmOutput) {
+		sender := t.transfer.SenderAddress
+		if len(sender) == 0 {
+			sender = defaultSender
+		}
+		value := t.transfer.Value
+		if value == nil {
+			value = big.NewInt(0)
+		}
+		scrData, err := e.getScrData(
+			i,
+			sender,
+			t.receiver,
+			originalSender,
+			value,
+			string(t.transfer.Data),
+			t.transfer.GasLimit,
+			gasPrice,
+			t.transfer.CallType,
+			prevTxHash,
+			txHash,
+		)
+		if err != nil {
+			return nil, err
+		}
+		scrsData = append(scrsData, scrData)
+	}
+	return scrsData, nil
+}
+
+func (e *Executor) getScrData(
+	index int,
+	sender []byte,
+	receiver []byte,
+	originalSender []byte,
+	value *big.Int,
+	data string,
+	gasLimit uint64,
+	gasPrice uint64,
+	callType vm.CallType,
+	prevTxHash string,
+	originalTxHash string,
+) (map[string]interface{}, error) {
+	hash, err := e.getScrHash(
+		index,
+		sender,
+		receiver,
+		originalSender,
+		value,
+		[]byte(data),
+		gasLimit,
+		gasPrice,
+		callType,
+		prevTxHash,
+		originalTxHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+	bechSender, err := bech32Encode(sender)
+	if err != nil {
+		return nil, err
+	}
+	bechReceiver, err := bech32Encode(receiver)
+	if err != nil {
+		return nil, err
+	}
+	bechOriginalSender, err := bech32Encode(originalSender)
+	if err != nil {
+		return nil, err
+	}
+	scrData := map[string]interface{}{
+		"hash":           hash,
+		"sender":         bechSender,
+		"receiver":       bechReceiver,
+		"originalSender": bechOriginalSender,
+		"value":          value.String(),
+		"data":           data,
+		"gasLimit":       gasLimit,
+		"gasPrice":       gasPrice,
+		"callType":       callType,
+		"prevTxHash":     prevTxHash,
+		"originalTxHash": originalTxHash,
+	}
+	return scrData, nil
+}
+
+// Like the node, a result is identified by the hash of its marshalled form,
+// computed like the hash of a tx. Its nonce is its index among the results of
+// its parent, which keeps identical results apart.
+func (e *Executor) getScrHash(
+	index int,
+	sender []byte,
+	receiver []byte,
+	originalSender []byte,
+	value *big.Int,
+	data []byte,
+	gasLimit uint64,
+	gasPrice uint64,
+	callType vm.CallType,
+	prevTxHash string,
+	originalTxHash string,
+) (string, error) {
+	scr := &smartContractResult.SmartContractResult{
+		Nonce:          uint64(index),
+		Value:          value,
+		RcvAddr:        receiver,
+		SndAddr:        sender,
+		Data:           data,
+		PrevTxHash:     e.scrHashBytes(originalTxHash, prevTxHash),
+		OriginalTxHash: e.txHashBytes(originalTxHash),
+		GasLimit:       gasLimit,
+		GasPrice:       gasPrice,
+		CallType:       callType,
+		OriginalSender: originalSender,
+	}
+	scrBytes, err := txMarshalizer.Marshal(scr)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(worldmock.DefaultHasher.Compute(string(scrBytes))), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/data/smartContractResult"
+	"github.com/multiversx/mx-chain-core-go/data/vm"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+)
+
+// Two identical transfers of a call lead to results with distinct hashes, each
+// the hash of the marshalled result.
+func TestScrHashesAreHashesOfTheMarshalledScrs(t *testing.T) {
+	e := newTestExecutor(t)
+	txHash := hex.EncodeToString(worldmock.DefaultHasher.Compute("tx"))
+	sender := uint64ToBytesAddress(1, false)
+	contract := uint64ToBytesAddress(1, true)
+	receiver := uint64ToBytesAddress(2, false)
+	transfer := vmcommon.OutputTransfer{Value: big.NewInt(1), SenderAddress: contract, CallType: vm.DirectCall}
+	vmOutput := &vmcommon.VMOutput{
+		OutputAccounts: map[string]*vmcommon.OutputAccount{
+			string(receiver): {
+				Address:         receiver,
+				OutputTransfers: []vmcommon.OutputTransfer{transfer, transfer},
+			},
+		},
+	}
+	scrsData, err := e.getOutputTransfersScrsData(txHash, txHash, sender, contract, 1_000_000_000, vmOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scrsData) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(scrsData))
+	}
+	scrBytes, err := txMarshalizer.Marshal(&smartContractResult.SmartContractResult{
+		Value:          big.NewInt(1),
+		RcvAddr:        receiver,
+		SndAddr:        contract,
+		Data:           []byte{},
+		PrevTxHash:     e.txHashBytes(txHash),
+		OriginalTxHash: e.txHashBytes(txHash),
+		GasPrice:       1_000_000_000,
+		CallType:       vm.DirectCall,
+		OriginalSender: sender,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedHash := hex.EncodeToString(worldmock.DefaultHasher.Compute(string(scrBytes)))
+	hash0 := scrsData[0].(map[string]interface{})["hash"]
+	hash1 := scrsData[1].(map[string]interface{})["hash"]
+	if hash0 != expectedHash {
+		t.Fatalf("expected hash %s, got %v", expectedHash, hash0)
+	}
+	if hash1 == hash0 {
+		t.Fatalf("expected distinct hashes, got %v twice", hash0)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/multiversx/mx-chain-core-go/hashing/keccak"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+)
+
+var (
+	txSignMarshalizer = &marshal.JsonMarshalizer{}
+	txSignHasher      = keccak.NewKeccak()
+)
+
+var errInvalidSignature = errors.New("transaction generation failed: ed25519: invalid signature")
+
+type bech32AddressEncoder struct{}
+
+func (encoder *bech32AddressEncoder) Encode(address []byte) (string, error) {
+	return bech32Encode(address)
+}
+
+func (encoder *bech32AddressEncoder) IsInterfaceNil() bool {
+	return encoder == nil
+}
+
+func verifyTxSignatures(rawTx RawTx) error {
+	tx, err := rawTxToTransaction(rawTx)
+	if err != nil {
+		return err
+	}
+	message, err := tx.GetDataForSigning(&bech32AddressEncoder{}, txSignMarshalizer, txSignHasher)
+	if err != nil {
+		return err
+	}
+	if !verifyEd25519Signature(tx.SndAddr, message, tx.Signature) {
+		return errInvalidSignature
+	}
+	if tx.HasOptionGuardianSet() {
+		if !verifyEd25519Signature(tx.GuardianAddr, message, tx.GuardianSignature) {
+			return errInvalidSignature
+		}
+	}
+	if len(tx.RelayerAddr) > 0 {
+		if !verifyEd25519Signature(tx.RelayerAddr, message, tx.RelayerSignature) {
+			return errInvalidSignature
+		}
+	}
+	return nil
+}
+
+func verifyEd25519Signature(publicKey []byte, message []byte, signature []byte) bool {
+	if len(publicKey) != ed25519.PublicKeySize || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(publicKey, message, signature)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	worldmock "github.com/multiversx/mx-chain-scenario-go/worldmock"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+)
+
+// The messages are the ones returned by the node API, so that tests fail the
+// same way on lightsimulnet and on a real network.
+var (
+	errInvalidSender          = errors.New("transaction generation failed: could not create sender address from provided param")
+	errInvalidReceiver        = errors.New("transaction generation failed: could not create receiver address from provided param")
+	errInvalidRelayer         = errors.New("transaction generation failed: could not create relayer address from provided param")
+	errDataFieldTooBig        = errors.New("transaction generation failed: data field is too big")
+	errInvalidChainID         = errors.New("transaction generation failed: invalid chain ID")
+	errInvalidTxVersion       = errors.New("transaction generation failed: invalid transaction version")
+	errInsufficientGasPrice   = errors.New("transaction generation failed: insufficient gas price in tx")
+	errInsufficientGasLimit   = errors.New("transaction generation failed: insufficient gas limit in tx")
+	errMoreGasThanMaxPerBlock = errors.New("transaction generation failed: more gas was provided than gas limit per block")
+	errAccountNotFound        = errors.New("transaction generation failed: account not found")
+	errInsufficientFunds      = errors.New("insufficient funds")
+)
+
+const maxTxDataSize = core.MegabyteSize
+
+// The checks applied to a tx, which simulations can lift without changing the
+// ones applied to the sent txs.
+type txValidation struct {
+	verifySignatures bool
+	checkMinGasPrice bool
+}
+
+func (e *Executor) sentTxValidation() txValidation {
+	return txValidation{
+		verifySignatures: e.verifySignatures,
+		checkMinGasPrice: true,
+	}
+}
+
+func (e *Executor) validateTx(rawTx RawTx, validation txValidation) error {
+	err := e.validateTxFields(rawTx, validation)
+	if err != nil {
+		return err
+	}
+	if validation.verifySignatures {
+		return verifyTxSignatures(rawTx)
+	}
+	return nil
+}
+
+func (e *Executor) validateTxFields(rawTx RawTx, validation txValidation) error {
+	if _, err := bech32Decode(rawTx.Sender); err != nil {
+		return errInvalidSender
+	}
+	if _, err := bech32Decode(rawTx.Receiver); err != nil {
+		return errInvalidReceiver
+	}
+	data, err := getRawTxData(rawTx)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxTxDataSize {
+		return errDataFieldTooBig
+	}
+	if rawTx.ChainID != e.network.ChainID {
+		return errInvalidChainID
+	}
+	if rawTx.Version < uint64(e.network.MinTransactionVersion) {
+		return errInvalidTxVersion
+	}
+	if e.network.MaxTransactionVersion != 0 && rawTx.Version > uint64(e.network.MaxTransactionVersion) {
+		return errInvalidTxVersion
+	}
+	if validation.checkMinGasPrice && rawTx.GasPrice < e.network.MinGasPrice {
+		return errInsufficientGasPrice
+	}
+	if rawTx.GasLimit < e.computeMoveBalanceGas(data) {
+		return errInsufficientGasLimit
+	}
+	if rawTx.GasLimit > e.network.MaxGasPerTransaction {
+		return errMoreGasThanMaxPerBlock
+	}
+	return nil
+}
+
+func (e *Executor) computeMoveBalanceGas(data []byte) uint64 {
+	return e.network.MinGasLimit + uint64(len(data))*e.network.GasPerDataByte
+}
+
+func (e *Executor) hasFundsForTx(senderAccount *worldmock.Account, rawTx RawTx, value *big.Int) bool {
+	maxFee := new(big.Int).Mul(
+		new(big.Int).SetUint64(rawTx.GasLimit),
+		new(big.Int).SetUint64(rawTx.GasPrice),
+	)
+	cost := new(big.Int).Add(value, maxFee)
+	return senderAccount.Balance.Cmp(cost) >= 0
+}
+
+// Like on the node, a tx the sender cannot pay for is still included as an
+// invalid tx: its nonce is consumed and the move balance fee is charged when
+// the balance covers it.
+func (e *Executor) setInsufficientFundsTx(txHash string, rawTx RawTx, senderAccount *worldmock.Account) error {
+	data, err := getRawTxData(rawTx)
+	if err != nil {
+		return err
+	}
+	gasUsed := e.computeMoveBalanceGas(data)
+	fee := new(big.Int).Mul(
+		new(big.Int).SetUint64(gasUsed),
+		new(big.Int).SetUint64(rawTx.GasPrice),
+	)
+	if senderAccount.Balance.Cmp(fee) < 0 {
+		fee = big.NewInt(0)
+	}
+	senderAccount.Balance = new(big.Int).Sub(senderAccount.Balance, fee)
+	senderAccount.Nonce += 1
+	if e.accumulateFees {
+		e.totalFees.Add(e.totalFees, fee)
+	}
+	e.txResps[txHash] = map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"hash":   txHash,
+			"status": "invalid",
+			"receipt": map[string]interface{}{
+				"value":  fee.String(),
+				"sender": rawTx.Sender,
+				"data":   errInsufficientFunds.Error(),
+				"txHash": txHash,
+			},
+			"executionReceipt": map[string]interface{}{
+				"returnCode":    vmcommon.OutOfFunds,
+				"returnMessage": errInsufficientFunds.Error(),
+			},
+			"gasUsed":          gasUsed,
+			"initiallyPaidFee": fee.String(),
+			"fee":              fee.String(),
+		},
+	}
+	e.txProcessStatusResps[txHash] = map[string]interface{}{
+		"status": "invalid",
+	}
+	e.keepTx(txHash)
+	return nil
+}
+
+// Data sent to a wallet is only a note attached to the transfer, unless it
+// calls a built-in function.
+func (e *Executor) isMoveBalanceData(receiver []byte, data []byte) bool {
+	if worldmock.IsSmartContractAddress(receiver) {
+		return false
+	}
+	function := strings.Split(string(data), "@")[0]
+	return !e.isBuiltinFunction(function)
+}
+
+func getRawTxData(rawTx RawTx) ([]byte, error) {
+	if rawTx.Data == nil {
+		return nil, nil
+	}
+	return base64.StdEncoding.DecodeString(*rawTx.Data)
+}
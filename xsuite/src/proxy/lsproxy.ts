@@ -39,6 +39,12 @@ export class LSProxy extends Proxy {
     return this.fetch("/admin/set-previous-block-info", block).then(() => {});
   }
 
+  generateBlocks(numBlocks: number) {
+    return this.fetch(`/simulator/generate-blocks/${numBlocks}`, {}).then(
+      () => {},
+    );
+  }
+
   /**
    * @deprecated Use `.getAllSerializableAccounts` instead.
    */
@@ -59,4 +65,5 @@ export type Block = {
   nonce?: number;
   round?: number;
   epoch?: number;
+  randomSeed?: string;
 };
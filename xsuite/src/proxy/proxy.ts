@@ -97,8 +97,11 @@ export class Proxy {
     const res = await this.fetch("/transaction/send-multiple", rawTxs);
     const txsHashesSent = getValuesInOrder(res.txsHashes) as string[];
     if (txsHashesSent.length !== rawTxs.length) {
+      const txsErrors = Object.entries(res.txsErrors ?? {})
+        .map(([i, error]) => `\n- tx ${i}: ${error}`)
+        .join("");
       throw new Error(
-        `Only ${txsHashesSent.length} of ${rawTxs.length} transactions were sent. The other ones were invalid.`,
+        `Only ${txsHashesSent.length} of ${rawTxs.length} transactions were sent. The other ones were invalid.${txsErrors}`,
       );
     }
     return txsHashesSent;
@@ -1,12 +1,25 @@
+import { createHash } from "node:crypto";
+import fs from "node:fs";
+import os from "node:os";
+import path from "node:path";
+import { Transaction, TransactionComputer } from "@multiversx/sdk-core";
+import { bech32 } from "bech32";
 import { expect, test } from "vitest";
 import { assertAccount, assertVs } from "../assert";
 import { e } from "../data";
 import {
+  fullBechAddress,
+  fullU8AAddress,
   zeroBechAddress,
   zeroHexAddress,
   zeroU8AAddress,
 } from "../data/address";
-import { getAddressShard, getAddressType } from "../data/utils";
+import {
+  getAddressShard,
+  getAddressType,
+  u8aToBase64,
+  u8aToHex,
+} from "../data/utils";
 import { LSWorld } from "./lsworld";
 import { createAddressLike } from "./utils";
 import { expandCode } from "./world";
@@ -42,6 +55,58 @@ test.concurrent("LSWorld.proxy.proxyUrl", async () => {
   expect(world.proxy.proxyUrl).toMatch(localhostRegex);
 });
 
+test.concurrent("LSWorld.proxy - /network/config", async () => {
+  using world = await LSWorld.start();
+  const { config } = await world.proxy.fetch("/network/config");
+  expect(config).toMatchObject({
+    erd_chain_id: "S",
+    erd_min_gas_limit: 50_000,
+    erd_gas_per_data_byte: 1_500,
+    erd_min_gas_price: 0,
+  });
+});
+
+test.concurrent(
+  "LSWorld.proxy - /network/config with network flags",
+  async () => {
+    using world = await LSWorld.start({
+      extraArgs: ["--chain-id", "C", "--min-gas-limit", "70000"],
+    });
+    const { config } = await world.proxy.fetch("/network/config");
+    expect(config).toMatchObject({
+      erd_chain_id: "C",
+      erd_min_gas_limit: 70_000,
+    });
+  },
+);
+
+test.concurrent(
+  "LSWorld.proxy - /network/config with config profile",
+  async () => {
+    const configDir = fs.mkdtempSync(path.join(os.tmpdir(), "lsworld-"));
+    const configPath = path.join(configDir, "profile.toml");
+    fs.writeFileSync(
+      configPath,
+      '[network]\nchain-id = "sov-1"\naddress-hrp = "sov"\nmin-gas-limit = 70000\n',
+    );
+    using world = await LSWorld.start({
+      extraArgs: ["--config", configPath, "--min-gas-limit", "80000"],
+    });
+    const { config } = await world.proxy.fetch("/network/config");
+    expect(config).toMatchObject({
+      erd_chain_id: "sov-1",
+      erd_min_gas_limit: 80_000,
+    });
+    const address = bech32.encode("sov", bech32.toWords(fullU8AAddress));
+    await world.proxy.fetch("/admin/set-accounts", [{ address, balance: "5" }]);
+    const { account } = await world.proxy.fetch(`/address/${address}`);
+    expect(account).toMatchObject({ address, balance: "5" });
+    await expect(
+      world.proxy.fetch(`/address/${fullBechAddress}`),
+    ).rejects.toThrow("invalid ERD address");
+  },
+);
+
 test.concurrent("LSWorld.getAccountNonce on empty bech address", async () => {
   using world = await LSWorld.start();
   expect(await world.getAccountNonce(zeroBechAddress)).toEqual(0);
@@ -221,6 +286,506 @@ test.concurrent("LSWorld.getAccountEsdtBalance", async () => {
   expect(balance).toEqual(1n);
 });
 
+test.concurrent("LSWorld.proxy - address esdt endpoints", async () => {
+  using world = await LSWorld.start();
+  const wallet = await world.createWallet({
+    kvs: {
+      esdts: [
+        { id: fftId, amount: 10 },
+        {
+          id: sftId,
+          roles: ["ESDTRoleNFTCreate"],
+          variants: [
+            { nonce: 1, amount: 2, name: "Name", royalties: 100, attrs: "01" },
+          ],
+        },
+      ],
+    },
+  });
+  const { esdts } = await world.proxy.fetch(`/address/${wallet}/esdt`);
+  expect(Object.keys(esdts).sort()).toEqual([fftId, `${sftId}-01`]);
+  const { tokenData: fftData } = await world.proxy.fetch(
+    `/address/${wallet}/esdt/${fftId}`,
+  );
+  expect(fftData).toEqual({ tokenIdentifier: fftId, balance: "10" });
+  const { tokenData: sftData } = await world.proxy.fetch(
+    `/address/${wallet}/nft/${sftId}/nonce/1`,
+  );
+  expect(sftData).toMatchObject({
+    balance: "2",
+    nonce: 1,
+    name: "Name",
+    royalties: "100",
+    attributes: "AQ==",
+  });
+  const { roles } = await world.proxy.fetch(`/address/${wallet}/esdts/roles`);
+  expect(roles).toEqual({ [sftId]: ["ESDTRoleNFTCreate"] });
+  const { tokens } = await world.proxy.fetch(
+    `/address/${wallet}/registered-nfts`,
+  );
+  expect(tokens).toEqual([sftId]);
+});
+
+test.concurrent("LSWallet.callContract - ESDT system SC", async () => {
+  using world = await LSWorld.start();
+  const esdtSystemSc =
+    "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqzllls8a5w6u";
+  const issueCost = 50_000_000_000_000_000n;
+  const wallet = await world.createWallet({ balance: issueCost });
+  const otherWallet = await world.createWallet();
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "issue",
+    funcArgs: [e.Str("MyToken"), e.Str("MTK"), e.U(1000), e.U(2)],
+    value: issueCost,
+    gasLimit: 60_000_000,
+  });
+  const { esdts } = await world.proxy.fetch(`/address/${wallet}/esdt`);
+  const [tokenId] = Object.keys(esdts);
+  expect(tokenId).toMatch(/^MTK-[0-9a-f]{6}$/);
+  expect(esdts[tokenId].balance).toEqual("1000");
+  assertAccount(await world.getAccount(esdtSystemSc), { balance: issueCost });
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "setSpecialRole",
+    funcArgs: [
+      e.Str(tokenId),
+      e.Addr(otherWallet),
+      e.Str("ESDTRoleLocalMint"),
+    ],
+    gasLimit: 60_000_000,
+  });
+  const { roles } = await world.proxy.fetch(
+    `/address/${otherWallet}/esdts/roles`,
+  );
+  expect(roles).toEqual({ [tokenId]: ["ESDTRoleLocalMint"] });
+});
+
+test.concurrent("LSWorld.proxy - esdt registry", async () => {
+  using world = await LSWorld.start();
+  const esdtSystemSc =
+    "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqzllls8a5w6u";
+  const issueCost = 50_000_000_000_000_000n;
+  const wallet = await world.createWallet({ balance: issueCost });
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "issue",
+    funcArgs: [
+      e.Str("MyToken"),
+      e.Str("MTK"),
+      e.U(1000),
+      e.U(2),
+      e.Str("canFreeze"),
+      e.Str("true"),
+    ],
+    value: issueCost,
+    gasLimit: 60_000_000,
+  });
+  const { tokens } = await world.proxy.fetch("/network/esdts");
+  expect(tokens).toHaveLength(1);
+  const [tokenId] = tokens;
+  expect(await world.proxy.fetch(`/esdt/${tokenId}/properties`)).toMatchObject(
+    {
+      tokenName: "MyToken",
+      tokenType: "FungibleESDT",
+      ownerAddress: wallet.toString(),
+      numDecimals: 2,
+      isPaused: false,
+      canFreeze: true,
+      canWipe: false,
+    },
+  );
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "setSpecialRole",
+    funcArgs: [
+      e.Str(tokenId),
+      e.Addr(wallet),
+      e.Str("ESDTRoleLocalMint"),
+      e.Str("ESDTRoleLocalBurn"),
+    ],
+    gasLimit: 60_000_000,
+  });
+  await wallet.callContract({
+    callee: wallet,
+    funcName: "ESDTLocalMint",
+    funcArgs: [e.Str(tokenId), e.U(100)],
+    gasLimit: 10_000_000,
+  });
+  await wallet.callContract({
+    callee: wallet,
+    funcName: "ESDTLocalBurn",
+    funcArgs: [e.Str(tokenId), e.U(10)],
+    gasLimit: 10_000_000,
+  });
+  expect(
+    await world.proxy.fetch(`/network/esdt/supply/${tokenId}`),
+  ).toEqual({
+    supply: "1090",
+    minted: "100",
+    burned: "10",
+    initialMinted: "1000",
+  });
+});
+
+test.concurrent("LSWorld.proxy - esdt registry pause", async () => {
+  using world = await LSWorld.start();
+  const esdtSystemSc =
+    "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqzllls8a5w6u";
+  const issueCost = 50_000_000_000_000_000n;
+  const wallet = await world.createWallet({ balance: issueCost });
+  const otherWallet = await world.createWallet();
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "issue",
+    funcArgs: [
+      e.Str("MyToken"),
+      e.Str("MTK"),
+      e.U(1000),
+      e.U(2),
+      e.Str("canPause"),
+      e.Str("true"),
+    ],
+    value: issueCost,
+    gasLimit: 60_000_000,
+  });
+  const {
+    tokens: [tokenId],
+  } = await world.proxy.fetch("/network/esdts");
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "pause",
+    funcArgs: [e.Str(tokenId)],
+    gasLimit: 60_000_000,
+  });
+  expect(await world.proxy.fetch(`/esdt/${tokenId}/properties`)).toMatchObject(
+    { isPaused: true },
+  );
+  await wallet
+    .transfer({
+      receiver: otherWallet,
+      esdts: [{ id: tokenId, amount: 1 }],
+      gasLimit: 10_000_000,
+    })
+    .assertFail({ code: 4, message: "esdt token is paused" });
+  expect(await wallet.getAccountNonce()).toEqual(3);
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "unPause",
+    funcArgs: [e.Str(tokenId)],
+    gasLimit: 60_000_000,
+  });
+  await wallet.transfer({
+    receiver: otherWallet,
+    esdts: [{ id: tokenId, amount: 1 }],
+    gasLimit: 10_000_000,
+  });
+  const { tokenData } = await world.proxy.fetch(
+    `/address/${otherWallet}/esdt/${tokenId}`,
+  );
+  expect(tokenData.balance).toEqual("1");
+});
+
+test.concurrent("LSWorld.proxy - esdt registry freeze", async () => {
+  using world = await LSWorld.start();
+  const esdtSystemSc =
+    "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqzllls8a5w6u";
+  const issueCost = 50_000_000_000_000_000n;
+  const wallet = await world.createWallet({ balance: issueCost });
+  const otherWallet = await world.createWallet();
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "issue",
+    funcArgs: [
+      e.Str("MyToken"),
+      e.Str("MTK"),
+      e.U(1000),
+      e.U(2),
+      e.Str("canFreeze"),
+      e.Str("true"),
+    ],
+    value: issueCost,
+    gasLimit: 60_000_000,
+  });
+  const {
+    tokens: [tokenId],
+  } = await world.proxy.fetch("/network/esdts");
+  await wallet.transfer({
+    receiver: otherWallet,
+    esdts: [{ id: tokenId, amount: 10 }],
+    gasLimit: 10_000_000,
+  });
+  await wallet.callContract({
+    callee: esdtSystemSc,
+    funcName: "freeze",
+    funcArgs: [e.Str(tokenId), e.Addr(otherWallet)],
+    gasLimit: 60_000_000,
+  });
+  await otherWallet
+    .transfer({
+      receiver: wallet,
+      esdts: [{ id: tokenId, amount: 1 }],
+      gasLimit: 10_000_000,
+    })
+    .assertFail({ code: 4, message: "account is frozen for this esdt token" });
+  await wallet
+    .transfer({
+      receiver: otherWallet,
+      esdts: [{ id: tokenId, amount: 1 }],
+      gasLimit: 10_000_000,
+    })
+    .assertFail({ code: 4, message: "account is frozen for this esdt token" });
+  const { tokenData } = await world.proxy.fetch(
+    `/address/${otherWallet}/esdt/${tokenId}`,
+  );
+  expect(tokenData.balance).toEqual("10");
+});
+
+test.concurrent("LSWorld.proxy - relayed tx", async () => {
+  using world = await LSWorld.start();
+  const relayer = await world.createWallet({ balance: 10n ** 18n });
+  const sender = await world.createWallet({ balance: 100 });
+  const receiver = await world.createWallet();
+  const gasPrice = 1_000_000_000;
+  const { txHash } = await world.proxy.fetch("/transaction/send", {
+    nonce: 0,
+    value: "10",
+    receiver: receiver.toString(),
+    sender: sender.toString(),
+    gasPrice,
+    gasLimit: 100_000,
+    signature: "",
+    chainID: "S",
+    version: 1,
+    relayer: relayer.toString(),
+    relayerSignature: "",
+  });
+  const { fee } = await world.proxy.resolveTx(txHash);
+  expect(fee).toEqual(100_000n * BigInt(gasPrice));
+  expect(await world.getAccountBalance(sender)).toEqual(90n);
+  expect(await world.getAccountNonce(sender)).toEqual(1);
+  expect(await world.getAccountBalance(relayer)).toEqual(10n ** 18n - fee);
+  expect(await world.getAccountNonce(relayer)).toEqual(0);
+  expect(await world.getAccountBalance(receiver)).toEqual(10n);
+});
+
+test.concurrent("LSWorld.proxy - signed relayed v2 tx", async () => {
+  using world = await LSWorld.start({ extraArgs: ["--verify-signatures"] });
+  const sender = world.newWalletFromFile_unsafe(
+    path.resolve("wallets", "keystore_key.json"),
+    "qpGjv7ZJ9gcPXWSN",
+  );
+  const relayer = world.newWalletFromFile_unsafe(
+    path.resolve("wallets", "keystore_mnemonic.json"),
+    "1234",
+  );
+  await world.setAccount({ address: sender, balance: 0 });
+  await world.setAccount({ address: relayer, balance: 10n ** 18n });
+  const receiver = await world.createWallet();
+  const gasPrice = 1_000_000_000;
+  const computer = new TransactionComputer();
+  const innerData = e.Str("hello").toTopU8A();
+  const getRelayedTx = async (innerGasLimit: bigint) => {
+    // The inner tx is signed with a gas limit of 0.
+    const innerTx = new Transaction({
+      nonce: 0n,
+      value: 0n,
+      receiver: receiver.toString(),
+      sender: sender.toString(),
+      gasPrice: BigInt(gasPrice),
+      gasLimit: innerGasLimit,
+      data: innerData,
+      chainID: "S",
+      version: 1,
+    });
+    const innerSignature = await sender.sign(
+      computer.computeBytesForSigning(innerTx),
+    );
+    const tx = new Transaction({
+      nonce: 0n,
+      value: 0n,
+      receiver: sender.toString(),
+      sender: relayer.toString(),
+      gasPrice: BigInt(gasPrice),
+      gasLimit: 1_000_000n,
+      data: new TextEncoder().encode(
+        [
+          "relayedTxV2",
+          receiver.toTopHex(),
+          "",
+          u8aToHex(innerData),
+          u8aToHex(innerSignature),
+        ].join("@"),
+      ),
+      chainID: "S",
+      version: 1,
+    });
+    const signature = await relayer.sign(computer.computeBytesForSigning(tx));
+    return {
+      nonce: 0,
+      value: "0",
+      receiver: sender.toString(),
+      sender: relayer.toString(),
+      gasPrice,
+      gasLimit: 1_000_000,
+      data: u8aToBase64(tx.data),
+      signature: u8aToHex(signature),
+      chainID: "S",
+      version: 1,
+    };
+  };
+  await expect(
+    world.proxy.fetch("/transaction/send", await getRelayedTx(500_000n)),
+  ).rejects.toThrow("invalid signature");
+  const { txHash } = await world.proxy.fetch(
+    "/transaction/send",
+    await getRelayedTx(0n),
+  );
+  const { fee } = await world.proxy.resolveTx(txHash);
+  expect(await world.getAccountNonce(sender)).toEqual(1);
+  expect(await world.getAccountNonce(relayer)).toEqual(1);
+  expect(await world.getAccountBalance(relayer)).toEqual(10n ** 18n - fee);
+});
+
+test.concurrent("LSWorld.proxy - protocol tx hashes", async () => {
+  using world = await LSWorld.start();
+  const sender = await world.createWallet({ balance: 10 });
+  const receiver = await world.createWallet();
+  const data = e.Str("hello").toTopU8A();
+  const signature = new Uint8Array(64).fill(1);
+  const rawTx = {
+    nonce: 0,
+    value: "1",
+    receiver: receiver.toString(),
+    sender: sender.toString(),
+    gasPrice: 0,
+    gasLimit: 100_000,
+    data: u8aToBase64(data),
+    signature: u8aToHex(signature),
+    chainID: "S",
+    version: 1,
+  };
+  const txHash = new TransactionComputer().computeTransactionHash(
+    new Transaction({
+      nonce: 0n,
+      value: 1n,
+      receiver: receiver.toString(),
+      sender: sender.toString(),
+      gasPrice: 0n,
+      gasLimit: 100_000n,
+      data,
+      signature,
+      chainID: "S",
+      version: 1,
+    }),
+  );
+  expect(await world.proxy.fetch("/transaction/send", rawTx)).toEqual({
+    txHash,
+  });
+  await expect(
+    world.proxy.fetch("/transaction/send", rawTx),
+  ).rejects.toThrow("duplicated transaction");
+});
+
+test.concurrent("LSWorld.proxy - simulate and cost", async () => {
+  using world = await LSWorld.start();
+  const wallet = await world.createWallet({ balance: 10n ** 18n });
+  const contract = await world.createContract({ code: worldCode });
+  const receiver = await world.createWallet();
+  const rawTx = {
+    nonce: 0,
+    value: "3",
+    receiver: contract.toString(),
+    sender: wallet.toString(),
+    gasPrice: 1_000_000_000,
+    gasLimit: 10_000_000,
+    data: btoa(`transfer_received@${receiver.toTopHex()}`),
+    signature: "",
+    chainID: "S",
+    version: 1,
+  };
+  const { result } = await world.proxy.fetch("/transaction/simulate", rawTx);
+  expect(result).toMatchObject({ status: "success", failReason: "" });
+  const { txGasUnits } = await world.proxy.fetch("/transaction/cost", rawTx);
+  expect(txGasUnits).toBeGreaterThan(0);
+  expect(txGasUnits).toEqual(result.gasUsed);
+  assertAccount(await wallet.getAccount(), { nonce: 0, balance: 10n ** 18n });
+  assertAccount(await receiver.getAccount(), { balance: 0 });
+  const { txHash } = await world.proxy.fetch("/transaction/send", rawTx);
+  expect(txHash).toEqual(result.hash);
+  const { gasUsed, fee } = await world.proxy.resolveTx(txHash);
+  expect({ gasUsed, fee: fee.toString() }).toEqual({
+    gasUsed: result.gasUsed,
+    fee: result.fee,
+  });
+  assertAccount(await receiver.getAccount(), { balance: 3 });
+});
+
+test.concurrent("LSWorld.proxy - snapshot and revert", async () => {
+  using world = await LSWorld.start({ extraArgs: ["--counter-tx-hashes"] });
+  const wallet = await world.createWallet({ balance: 10 });
+  const receiver = await world.createWallet();
+  const { id } = await world.proxy.fetch("/admin/snapshot", {});
+  const txHash = await wallet.sendTransfer({
+    receiver,
+    value: 4,
+    gasLimit: 10_000_000,
+  });
+  await world.proxy.resolveTx(txHash);
+  await world.setCurrentBlockInfo({ nonce: 10 });
+  await world.proxy.fetch(`/admin/revert/${id}`, {});
+  assertAccount(await wallet.getAccount(), { nonce: 0, balance: 10 });
+  assertAccount(await receiver.getAccount(), { balance: 0 });
+  expect((await world.getNetworkStatus()).nonce).toEqual(0);
+  expect(
+    await wallet.sendTransfer({ receiver, value: 4, gasLimit: 10_000_000 }),
+  ).toEqual(txHash);
+  await world.proxy.fetch(`/admin/delete-snapshot/${id}`, {});
+  await expect(
+    world.proxy.fetch(`/admin/revert/${id}`, {}),
+  ).rejects.toThrow("unknown snapshot");
+});
+
+test.concurrent("LSWorld.proxy - save and load state", async () => {
+  const stateDir = fs.mkdtempSync(path.join(os.tmpdir(), "lsworld-"));
+  const statePath = path.join(stateDir, "state.json");
+  const contractAccount = {
+    balance: 3,
+    code: worldCode,
+    kvs: [[e.Str("n"), e.U64(10)]],
+  };
+  using world = await LSWorld.start({ extraArgs: ["--state-dir", stateDir] });
+  const wallet = await world.createWallet({
+    balance: 10,
+    kvs: { esdts: [{ id: fftId, amount: 5 }] },
+  });
+  const contract = await world.createContract(contractAccount);
+  await world.setCurrentBlockInfo({ epoch: 5 });
+  expect(
+    await world.proxy.fetch("/admin/save-state", { path: statePath }),
+  ).toEqual({ path: statePath });
+  await expect(
+    world.proxy.fetch("/admin/save-state", {
+      path: path.join(stateDir, "..", "state.json"),
+    }),
+  ).rejects.toThrow(
+    "state file path is neither the state file nor in the state directory",
+  );
+  await world.updateAccount({ address: wallet, balance: 1 });
+  await world.setCurrentBlockInfo({ epoch: 6 });
+  await world.proxy.fetch("/admin/load-state", { path: statePath });
+  assertAccount(await wallet.getAccount(), {
+    balance: 10,
+    kvs: { esdts: [{ id: fftId, amount: 5 }] },
+  });
+  expect((await world.getNetworkStatus()).epoch).toEqual(5);
+  using loadedWorld = await LSWorld.start({
+    extraArgs: ["--load-state", statePath],
+  });
+  assertAccount(await loadedWorld.getAccount(contract), contractAccount);
+  expect((await loadedWorld.getNetworkStatus()).epoch).toEqual(5);
+});
+
 test.concurrent("LSWorld.getAccountValue - non-present key", async () => {
   using world = await LSWorld.start();
   const wallet = await world.createWallet({ kvs: { "01": "11" } });
@@ -365,6 +930,47 @@ test.concurrent("LSWorld.setAccount", async () => {
   });
 });
 
+test.concurrent(
+  "LSWorld.proxy - set-accounts with structured esdts",
+  async () => {
+    using world = await LSWorld.start();
+    const wallet = await world.createWallet();
+    await world.proxy.fetch("/admin/set-accounts", [
+      {
+        address: wallet.toString(),
+        esdts: [
+          { id: fftId, amount: "10" },
+          { id: sftId, nonce: 1, amount: "2", name: "Name", royalties: 100 },
+        ],
+        roles: { [sftId]: ["ESDTRoleNFTCreate"] },
+        lastNonce: { [sftId]: 1 },
+      },
+    ]);
+    assertAccount(await wallet.getAccount(), {
+      kvs: {
+        esdts: [
+          { id: fftId, amount: 10 },
+          {
+            id: sftId,
+            roles: ["ESDTRoleNFTCreate"],
+            lastNonce: 1,
+            variants: [{ nonce: 1, amount: 2, name: "Name", royalties: 100 }],
+          },
+        ],
+      },
+    });
+    const { account } = await world.proxy.fetch(
+      `/address/${wallet}?withEsdts=true`,
+    );
+    expect(account.esdts).toMatchObject([
+      { id: fftId, nonce: 0, amount: "10" },
+      { id: sftId, nonce: 1, amount: "2", name: "Name", royalties: 100 },
+    ]);
+    expect(account.roles).toEqual({ [sftId]: ["ESDTRoleNFTCreate"] });
+    expect(account.lastNonce).toEqual({ [sftId]: 1 });
+  },
+);
+
 test.concurrent("LSWorld.updateAccount", async () => {
   using world = await LSWorld.start();
   const wallet = await world.createWallet({
@@ -423,6 +1029,45 @@ test.concurrent("LSWorld.setCurrentBlockInfo", async () => {
   assertVs(returnData, [e.U64(100), e.U64(200), e.U64(300), e.U64(400)]);
 });
 
+test.concurrent("LSWorld.setCurrentBlockInfo - randomSeed", async () => {
+  using world = await LSWorld.start();
+  await world.setCurrentBlockInfo({ randomSeed: "01".repeat(48) });
+  await expect(
+    world.setCurrentBlockInfo({ randomSeed: "01".repeat(32) }),
+  ).rejects.toThrow("random seed must be 48 bytes long");
+});
+
+test.concurrent(
+  "LSWorld.setCurrentBlockInfo - randomSeed derived from --random-seed",
+  async () => {
+    using world = await LSWorld.start({
+      extraArgs: ["--random-seed", "0badc0de"],
+    });
+    const contract = await world.createContract({ code: worldCode });
+    // Returns the seed of the current block, through getBlockRandomSeed.
+    const seedContract = await world.createContract({
+      code: "0061736d01000000010d0360000060017f0060027f7f0002270203656e7612676574426c6f636b52616e646f6d53656564000103656e760666696e697368000203030200000503010001072303066d656d6f7279020004696e697400020f6765745f72616e646f6d5f7365656400030a110202000b0c00410010004100413010010b",
+    });
+    await world.setCurrentBlockInfo({ round: 2 });
+    const { returnData } = await world.query({
+      callee: contract,
+      funcName: "get_current_block_info",
+    });
+    assertVs(returnData, [e.U64(0), e.U64(0), e.U64(2), e.U64(0)]);
+    const {
+      returnData: [randomSeed],
+    } = await world.query({
+      callee: seedContract,
+      funcName: "get_random_seed",
+    });
+    expect(randomSeed).toEqual(
+      createHash("sha384")
+        .update(Buffer.from("0badc0de" + "0000000000000002", "hex"))
+        .digest("hex"),
+    );
+  },
+);
+
 test.concurrent("LSWorld.setPreviousBlockInfo", async () => {
   using world = await LSWorld.start();
   const contract = await world.createContract({ code: worldCode });
@@ -471,6 +1116,144 @@ test.concurrent("LSWorld.advanceEpoch", async () => {
   expect((await world.getNetworkStatus()).epoch).toEqual(20);
 });
 
+test.concurrent("LSWorld.generateBlocks", async () => {
+  using world = await LSWorld.start();
+  await world.generateBlocks(3);
+  const { blockTimestamp, nonce, round } = await world.getNetworkStatus();
+  expect({ blockTimestamp, nonce, round }).toEqual({
+    blockTimestamp: 18,
+    nonce: 3,
+    round: 3,
+  });
+});
+
+test.concurrent("LSWorld.generateBlocks - too many blocks", async () => {
+  using world = await LSWorld.start();
+  await expect(world.generateBlocks(10_001)).rejects.toThrow(
+    "too many blocks to generate, the maximum is 10000",
+  );
+  expect((await world.getNetworkStatus()).nonce).toEqual(0);
+});
+
+test.concurrent("LSWorld.generateBlocks - mempool", async () => {
+  using world = await LSWorld.start({ extraArgs: ["--mempool"] });
+  const wallet1 = await world.createWallet({ balance: 2 });
+  const wallet2 = await world.createWallet();
+  const txHashes = await world.sendTransfers([
+    { sender: wallet1, receiver: wallet2, value: 1, gasLimit: 10_000_000 },
+    { sender: wallet1, receiver: wallet2, value: 1, gasLimit: 10_000_000 },
+  ]);
+  assertAccount(await wallet2.getAccount(), { balance: 0 });
+  await world.generateBlocks(1);
+  await world.proxy.resolveTxs(txHashes);
+  assertAccount(await wallet1.getAccount(), { balance: 0 });
+  assertAccount(await wallet2.getAccount(), { balance: 2 });
+});
+
+test.concurrent("LSWorld.generateBlocks - cross-shard transfer", async () => {
+  using world = await LSWorld.start({ extraArgs: ["--num-shards", "3"] });
+  const wallet0 = await world.createWallet({
+    address: { shard: 0 },
+    balance: 2,
+  });
+  const wallet1 = await world.createWallet({ address: { shard: 1 } });
+  const txHash = await world.sendTransfer({
+    sender: wallet0,
+    receiver: wallet1,
+    value: 1,
+    gasLimit: 10_000_000,
+  });
+  assertAccount(await wallet0.getAccount(), { balance: 1 });
+  assertAccount(await wallet1.getAccount(), { balance: 0 });
+  await world.generateBlocks(1);
+  await world.proxy.resolveTx(txHash);
+  assertAccount(await wallet1.getAccount(), { balance: 1 });
+});
+
+test.concurrent(
+  "LSWorld.generateBlocks - cross-shard async call with callback",
+  async () => {
+    using world = await LSWorld.start({ extraArgs: ["--num-shards", "3"] });
+    const issueCost = 50_000_000_000_000_000n;
+    const wallet = await world.createWallet({
+      address: { shard: 0 },
+      balance: issueCost,
+    });
+    const contract = await world.createContract({
+      address: { shard: 1 },
+      code: worldCode,
+    });
+    const txHash = await world.sendCallContract({
+      sender: wallet,
+      callee: contract,
+      funcName: "issue_token_with_succeeding_callback_v2",
+      value: issueCost,
+      gasLimit: 100_000_000,
+    });
+    await world.generateBlocks(1);
+    const { status } = await world.proxy.fetch(
+      `/transaction/${txHash}/process-status`,
+    );
+    expect(status).toEqual("pending");
+    await world.generateBlocks(2);
+    const { tx } = await world.proxy.resolveTx(txHash);
+    expect(tx.status).toEqual("success");
+    const { esdts } = await world.proxy.fetch(`/address/${contract}/esdt`);
+    const [tokenId] = Object.keys(esdts);
+    expect(tokenId).toMatch(/^TEST-[0-9a-f]{6}$/);
+    expect(esdts[tokenId].balance).toEqual("1");
+  },
+);
+
+test.concurrent(
+  "LSWorld.generateBlocks - cross-shard call failing in destination",
+  async () => {
+    using world = await LSWorld.start({ extraArgs: ["--num-shards", "3"] });
+    const wallet = await world.createWallet({ address: { shard: 0 } });
+    const contract = await world.createContract({
+      address: { shard: 1 },
+      code: worldCode,
+    });
+    const txHash = await world.sendCallContract({
+      sender: wallet,
+      callee: contract,
+      funcName: "failing_endpoint",
+      gasLimit: 10_000_000,
+    });
+    await world.generateBlocks(1);
+    const txResult = await world.proxy.resolveTxResult(txHash);
+    expect(txResult).toMatchObject({
+      type: "fail",
+      errorCode: "signalError",
+      errorMessage: "Fail",
+    });
+    const { status } = await world.proxy.fetch(
+      `/transaction/${txHash}/process-status`,
+    );
+    expect(status).toEqual("failed");
+  },
+);
+
+test.concurrent("LSWorld.start - auto-advance per tx", async () => {
+  using world = await LSWorld.start({
+    extraArgs: ["--auto-advance", "tx", "--rounds-per-epoch", "2"],
+  });
+  const wallet1 = await world.createWallet({ balance: 2 });
+  const wallet2 = await world.createWallet();
+  await world.doTransfers([
+    { sender: wallet1, receiver: wallet2, value: 1, gasLimit: 10_000_000 },
+    { sender: wallet1, receiver: wallet2, value: 1, gasLimit: 10_000_000 },
+  ]);
+  const { blockTimestamp, nonce, round, epoch } =
+    await world.getNetworkStatus();
+  expect({ blockTimestamp, nonce, round, epoch }).toEqual({
+    blockTimestamp: 12,
+    nonce: 2,
+    round: 2,
+    epoch: 1,
+  });
+});
+
 test.concurrent("LSWorld.query - basic", async () => {
   using world = await LSWorld.start();
   const contract = await world.createContract({
@@ -605,10 +1388,64 @@ test.concurrent(
         value: 0,
         gasLimit: 0,
       }),
-    ).rejects.toThrow("insufficient gas limit");
+    ).rejects.toThrow(
+      "transaction generation failed: insufficient gas limit in tx",
+    );
+  },
+);
+
+test.concurrent("LSWorld.executeTx - fee with gas price modifier", async () => {
+  using world = await LSWorld.start({ gasPrice: 1_000_000_000 });
+  const wallet1 = await world.createWallet({ balance: 10n ** 18n });
+  const wallet2 = await world.createWallet();
+  const { gasUsed, fee } = await wallet1.executeTx({
+    receiver: wallet2,
+    value: 1,
+    gasLimit: 100_000,
+  });
+  expect(gasUsed).toEqual(100_000);
+  expect(fee).toEqual(50_000n * 10n ** 9n + 50_000n * 10n ** 7n);
+  assertAccount(await wallet1.getAccount(), {
+    balance: 10n ** 18n - fee - 1n,
+  });
+});
+
+test.concurrent(
+  "LSWorld.transfer - invalid tx - insufficient funds",
+  async () => {
+    using world = await LSWorld.start();
+    const wallet1 = await world.createWallet();
+    const wallet2 = await world.createWallet();
+    await wallet1
+      .transfer({
+        receiver: wallet2,
+        value: 1,
+        gasLimit: 50_000,
+      })
+      .assertFail({ code: "invalid", message: "insufficient funds" });
+    assertAccount(await wallet1.getAccount(), { nonce: 1, balance: 0 });
+    assertAccount(await wallet2.getAccount(), { balance: 0 });
   },
 );
 
+test.concurrent("LSWorld.transfer - verify-signatures", async () => {
+  using world = await LSWorld.start({ extraArgs: ["--verify-signatures"] });
+  const signedWallet = world.newWalletFromFile_unsafe(
+    path.resolve("wallets", "keystore_key.json"),
+    "qpGjv7ZJ9gcPXWSN",
+  );
+  await world.setAccount({ address: signedWallet, balance: 10 });
+  const unsignedWallet = await world.createWallet({ balance: 10 });
+  const receiver = await world.createWallet();
+  await signedWallet.transfer({ receiver, value: 1, gasLimit: 50_000 });
+  await expect(
+    unsignedWallet.transfer({ receiver, value: 1, gasLimit: 50_000 }),
+  ).rejects.toThrow("ed25519: invalid signature");
+  assertAccount(await signedWallet.getAccount(), { nonce: 1, balance: 9 });
+  assertAccount(await unsignedWallet.getAccount(), { nonce: 0, balance: 10 });
+  assertAccount(await receiver.getAccount(), { balance: 1 });
+});
+
 test.concurrent("LSWorld.doTransfers - 100 transfers", async () => {
   using world = await LSWorld.start();
   const wallet1 = await world.createWallet({
@@ -635,6 +1472,142 @@ test.concurrent("LSWorld.doTransfers - 100 transfers", async () => {
   });
 });
 
+const singleEsdtTransferCases: {
+  name: string;
+  toContract: boolean;
+  getTx: (
+    sender: string,
+    receiver: string,
+    funcDataParts: string[],
+  ) => { receiver: string; dataParts: string[] };
+  esdt: { id: string; nonce?: number; amount: number };
+}[] = [
+  {
+    name: "ESDTTransfer to wallet",
+    toContract: false,
+    getTx: (_sender, receiver, funcDataParts) => ({
+      receiver,
+      dataParts: [
+        "ESDTTransfer",
+        e.Str(fftId).toTopHex(),
+        e.U(10).toTopHex(),
+        ...funcDataParts,
+      ],
+    }),
+    esdt: { id: fftId, amount: 10 },
+  },
+  {
+    name: "ESDTTransfer to contract",
+    toContract: true,
+    getTx: (_sender, receiver, funcDataParts) => ({
+      receiver,
+      dataParts: [
+        "ESDTTransfer",
+        e.Str(fftId).toTopHex(),
+        e.U(10).toTopHex(),
+        ...funcDataParts,
+      ],
+    }),
+    esdt: { id: fftId, amount: 10 },
+  },
+  {
+    name: "ESDTNFTTransfer to wallet",
+    toContract: false,
+    getTx: (sender, receiver, funcDataParts) => ({
+      receiver: sender,
+      dataParts: [
+        "ESDTNFTTransfer",
+        e.Str(sftId).toTopHex(),
+        e.U(1).toTopHex(),
+        e.U(10).toTopHex(),
+        e.Addr(receiver).toTopHex(),
+        ...funcDataParts,
+      ],
+    }),
+    esdt: { id: sftId, nonce: 1, amount: 10 },
+  },
+  {
+    name: "ESDTNFTTransfer to contract",
+    toContract: true,
+    getTx: (sender, receiver, funcDataParts) => ({
+      receiver: sender,
+      dataParts: [
+        "ESDTNFTTransfer",
+        e.Str(sftId).toTopHex(),
+        e.U(1).toTopHex(),
+        e.U(10).toTopHex(),
+        e.Addr(receiver).toTopHex(),
+        ...funcDataParts,
+      ],
+    }),
+    esdt: { id: sftId, nonce: 1, amount: 10 },
+  },
+];
+
+for (const { name, toContract, getTx, esdt } of singleEsdtTransferCases) {
+  test.concurrent(`LSWorld.executeTx - ${name}`, async () => {
+    using world = await LSWorld.start();
+    const wallet = await world.createWallet({
+      kvs: {
+        esdts: [
+          { id: fftId, amount: 10 },
+          { id: sftId, nonce: 1, amount: 10 },
+        ],
+      },
+    });
+    const otherWallet = await world.createWallet();
+    const contract = await world.createContract({ code: worldCode });
+    const { receiver, dataParts } = getTx(
+      wallet.toString(),
+      toContract ? contract.toString() : otherWallet.toString(),
+      toContract
+        ? [e.Str("transfer_received").toTopHex(), otherWallet.toTopHex()]
+        : [],
+    );
+    await world.executeTx({
+      sender: wallet,
+      receiver,
+      data: dataParts.join("@"),
+      gasLimit: 10_000_000,
+    });
+    assertAccount(await otherWallet.getAccount(), {
+      kvs: { esdts: [esdt] },
+    });
+  });
+}
+
+test.concurrent(
+  "LSWorld.transfer - concurrent with queries and reads",
+  async () => {
+    using world = await LSWorld.start();
+    const receiver = await world.createWallet();
+    const contract = await world.createContract({ code: worldCode });
+    const wallets = await Promise.all(
+      Array.from({ length: 10 }, () => world.createWallet({ balance: 10 })),
+    );
+    await Promise.all(
+      wallets.flatMap((wallet) => [
+        (async () => {
+          for (let i = 0; i < 10; i++) {
+            await wallet.transfer({ receiver, value: 1, gasLimit: 10_000_000 });
+          }
+        })(),
+        (async () => {
+          for (let i = 0; i < 10; i++) {
+            await world.query({
+              callee: contract,
+              funcName: "get_caller",
+              sender: wallet,
+            });
+            await receiver.getAccount();
+          }
+        })(),
+      ]),
+    );
+    assertAccount(await receiver.getAccount(), { balance: 100 });
+  },
+);
+
 test.concurrent(
   "LSWorld.doTransfers - invalid tx - gasLimit too low",
   async () => {
@@ -650,7 +1623,7 @@ test.concurrent(
         },
       ]),
     ).rejects.toThrow(
-      "Only 0 of 1 transactions were sent. The other ones were invalid.",
+      "Only 0 of 1 transactions were sent. The other ones were invalid.\n- tx 0: transaction generation failed: insufficient gas limit in tx",
     );
   },
 );
@@ -676,6 +1649,32 @@ test.concurrent("LSWorld.deployContract", async () => {
   });
 });
 
+test.concurrent("LSWorld.deployContract - protocol addresses", async () => {
+  using world = await LSWorld.start({
+    extraArgs: ["--protocol-sc-addresses"],
+  });
+  const wallet = await world.createWallet({ address: { shard: 1 } });
+  const { contract: contract0 } = await world.deployContract({
+    sender: wallet,
+    code: worldCode,
+    codeArgs: [e.U64(1)],
+    gasLimit: 40_000_000,
+  });
+  const { contract: contract1 } = await world.deployContract({
+    sender: wallet,
+    code: worldCode,
+    codeArgs: [e.U64(1)],
+    gasLimit: 40_000_000,
+  });
+  expect(getAddressType(contract0)).toEqual("vmContract");
+  expect(getAddressShard(contract0)).toEqual(1);
+  expect(contract0.toString()).not.toEqual(contract1.toString());
+  assertAccount(await contract1.getAccount(), {
+    code: worldCode,
+    kvs: [[e.Str("n"), e.U64(1)]],
+  });
+});
+
 test.concurrent("LSWorld.upgradeContract", async () => {
   using world = await LSWorld.start();
   const wallet = await world.createWallet();
@@ -716,6 +1715,34 @@ test.concurrent("LSWorld.callContract", async () => {
   });
 });
 
+test.concurrent("LSWorld.callContract - ClaimDeveloperRewards", async () => {
+  using world = await LSWorld.start({ gasPrice: 1_000_000_000 });
+  const wallet = await world.createWallet({ balance: 10n ** 18n });
+  const contract = await wallet.createContract({ code: worldCode });
+  await wallet.callContract({
+    callee: contract,
+    funcName: "set_n",
+    funcArgs: [e.U64(1)],
+    gasLimit: 10_000_000,
+  });
+  const { account } = await world.proxy.fetch(`/address/${contract}`);
+  const developerReward = BigInt(account.developerReward);
+  expect(developerReward > 0n).toEqual(true);
+  const balance = await wallet.getAccountBalance();
+  const { fee } = await wallet.callContract({
+    callee: contract,
+    funcName: "ClaimDeveloperRewards",
+    gasLimit: 10_000_000,
+  });
+  expect(await wallet.getAccountBalance()).toEqual(
+    balance + developerReward - fee,
+  );
+  const { account: accountAfter } = await world.proxy.fetch(
+    `/address/${contract}`,
+  );
+  expect(accountAfter.developerReward).toEqual("0");
+});
+
 test.concurrent("LSWorld.addKvs", async () => {
   using world = await LSWorld.start();
   const wallet = await world.createWallet({
@@ -1167,6 +2194,67 @@ test.concurrent(
   },
 );
 
+test.concurrent("LSWallet.callContract - VM log events", async () => {
+  using world = await LSWorld.start();
+  const wallet = await world.createWallet({
+    kvs: { esdts: [{ id: fftId, amount: 10 }] },
+  });
+  const contract = await wallet.createContract({ code: worldCode });
+  const receiver = await world.createWallet();
+  const { tx } = await wallet.callContract({
+    callee: contract,
+    funcName: "transfer_received",
+    funcArgs: [receiver],
+    esdts: [{ id: fftId, amount: 10 }],
+    gasLimit: 10_000_000,
+  });
+  const esdtTopics = [u8aToBase64(e.Str(fftId).toTopU8A()), "", "Cg=="];
+  expect(tx.logs.events).toMatchObject([
+    {
+      address: wallet.toString(),
+      identifier: "ESDTTransfer",
+      topics: [...esdtTopics, u8aToBase64(contract.toTopU8A())],
+    },
+    {
+      address: contract.toString(),
+      identifier: "ESDTTransfer",
+      topics: [...esdtTopics, u8aToBase64(receiver.toTopU8A())],
+    },
+    { address: wallet.toString(), identifier: "writeLog" },
+    { address: contract.toString(), identifier: "completedTxEvent" },
+  ]);
+});
+
+test.concurrent("LSWallet.callContract - smart contract results", async () => {
+  using world = await LSWorld.start({ gasPrice: 1_000_000_000 });
+  const wallet = await world.createWallet({ balance: 10n ** 18n });
+  const contract = await wallet.createContract({ code: worldCode });
+  const receiver = await world.createWallet();
+  const { tx } = await wallet.callContract({
+    callee: contract,
+    funcName: "transfer_received",
+    funcArgs: [receiver],
+    value: 3,
+    gasLimit: 10_000_000,
+  });
+  const scrBase = {
+    sender: contract.toString(),
+    originalSender: wallet.toString(),
+    prevTxHash: tx.hash,
+    originalTxHash: tx.hash,
+    callType: 0,
+  };
+  expect(tx.smartContractResults).toMatchObject([
+    { ...scrBase, receiver: receiver.toString(), value: "3", data: "" },
+    {
+      ...scrBase,
+      receiver: wallet.toString(),
+      value: (BigInt(tx.initiallyPaidFee) - BigInt(tx.fee)).toString(),
+      data: "@6f6b",
+    },
+  ]);
+});
+
 test.concurrent("LSWallet.callContract - failure", async () => {
   using world = await LSWorld.start();
   const wallet = await world.createWallet();
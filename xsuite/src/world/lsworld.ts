@@ -193,6 +193,10 @@ export class LSWorld extends World {
     });
   }
 
+  generateBlocks(numBlocks: number) {
+    return this.proxy.generateBlocks(numBlocks);
+  }
+
   resolveDeployContracts(txHashes: string[]) {
     return super
       .resolveDeployContracts(txHashes)